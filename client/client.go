@@ -0,0 +1,72 @@
+// Package client implements the client half of MCP on top of a
+// transport.Interface.
+package client
+
+import (
+	"context"
+
+	"github.com/KunalSin9h/modern-mcp-go/client/transport"
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// Client is a transport-agnostic MCP client. It wraps a transport.Interface
+// and adds nothing method-specific of its own; callers send requests and
+// notifications through it and, for transports that support it, handle
+// requests the server issues back.
+type Client struct {
+	transport transport.Interface
+}
+
+// NewClient creates a Client on top of the given transport. The transport
+// must still be started with Start before requests can be sent.
+func NewClient(t transport.Interface) *Client {
+	return &Client{transport: t}
+}
+
+// Start starts the underlying transport.
+func (c *Client) Start(ctx context.Context) error {
+	return c.transport.Start(ctx)
+}
+
+// SendRequest sends request to the server and waits for its response.
+func (c *Client) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	return c.transport.SendRequest(ctx, request)
+}
+
+// SendRequestStreaming behaves like SendRequest, except that on a transport
+// implementing transport.StreamingSender, a result of at least threshold
+// bytes is left out of the response's Result and handed back via
+// ResultReader instead, which the caller must then close. Transports that
+// don't implement transport.StreamingSender fall back to SendRequest, in
+// which case ResultReader is always nil.
+func (c *Client) SendRequestStreaming(ctx context.Context, request transport.JSONRPCRequest, threshold int64) (*transport.JSONRPCResponse, error) {
+	streamer, ok := c.transport.(transport.StreamingSender)
+	if !ok {
+		return c.transport.SendRequest(ctx, request)
+	}
+	return streamer.SendRequestStreaming(ctx, request, threshold)
+}
+
+// SendNotification sends a notification to the server.
+func (c *Client) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return c.transport.SendNotification(ctx, notification)
+}
+
+// OnNotification registers the handler invoked for notifications sent by
+// the server.
+func (c *Client) OnNotification(handler func(notification mcp.JSONRPCNotification)) {
+	c.transport.SetNotificationHandler(handler)
+}
+
+// RegisterRequestHandler registers the handler invoked when the server
+// issues a request back to this client, such as sampling/createMessage,
+// roots/list, or elicitation/create. Only transports that support
+// server-initiated requests will ever invoke it.
+func (c *Client) RegisterRequestHandler(method string, handler transport.RequestHandlerFunc) {
+	c.transport.RegisterRequestHandler(method, handler)
+}
+
+// Close closes the underlying transport.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
@@ -6,7 +6,7 @@ import (
 )
 
 // NewInProcessClient connect directly to a mcp server object in the same process
-func NewInProcessClient(server *server.MCPServer) (*Client, error) {
-	inProcessTransport := transport.NewInProcessTransport(server)
+func NewInProcessClient(server *server.MCPServer, opts ...transport.InProcessTransportOption) (*Client, error) {
+	inProcessTransport := transport.NewInProcessTransport(server, opts...)
 	return NewClient(inProcessTransport), nil
 }
@@ -0,0 +1,191 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// startMockBatchServer starts a StreamableHTTP server that echoes each
+// request in a batch back as its own response, in whatever order the
+// handler happens to iterate the batch in (map iteration over the decoded
+// array, which Go does not guarantee preserves order), so tests exercise
+// the client's by-ID matching rather than relying on server-side order.
+func startMockBatchServer() (string, func()) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var elements []map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&elements); err != nil {
+			http.Error(w, fmt.Sprintf("invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		var responses []map[string]any
+		for _, element := range elements {
+			id, hasID := element["id"]
+			if !hasID {
+				// A notification: no response.
+				continue
+			}
+			if element["method"] == "debug/drop" {
+				// Simulate a server that silently drops a request instead
+				// of answering it, so the client's handling of a missing
+				// response can be tested.
+				continue
+			}
+			responses = append(responses, map[string]any{
+				"jsonrpc": "2.0",
+				"id":      id,
+				"result":  element,
+			})
+		}
+
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, testServer.Close
+}
+
+func TestStreamableHTTPSendBatch(t *testing.T) {
+	url, closeF := startMockBatchServer()
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("EmptyBatch", func(t *testing.T) {
+		responses, err := trans.SendBatch(ctx, nil)
+		if err != nil {
+			t.Fatalf("SendBatch failed: %v", err)
+		}
+		if responses != nil {
+			t.Errorf("expected nil responses for empty batch, got %v", responses)
+		}
+	})
+
+	t.Run("MixedRequestsInOrder", func(t *testing.T) {
+		requests := []JSONRPCRequest{
+			{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo", Params: map[string]any{"n": 1}},
+			{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(2)), Method: "debug/echo", Params: map[string]any{"n": 2}},
+			{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(3)), Method: "debug/echo", Params: map[string]any{"n": 3}},
+		}
+
+		responses, err := trans.SendBatch(ctx, requests)
+		if err != nil {
+			t.Fatalf("SendBatch failed: %v", err)
+		}
+		if len(responses) != len(requests) {
+			t.Fatalf("expected %d responses, got %d", len(requests), len(responses))
+		}
+		for i, resp := range responses {
+			if resp == nil {
+				t.Fatalf("response %d: expected a response, got nil", i)
+			}
+			wantID := requests[i].ID.Value().(int64)
+			if gotID, ok := resp.ID.Value().(int64); !ok || gotID != wantID {
+				t.Errorf("response %d: expected ID %d, got %v", i, wantID, resp.ID.Value())
+			}
+		}
+	})
+
+	t.Run("MissingResponseLeavesNil", func(t *testing.T) {
+		// The mock server never responds to method "debug/drop"; alongside
+		// a request it does answer, the client should return a same-length
+		// slice with a nil in the dropped request's place.
+		requests := []JSONRPCRequest{
+			{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(10)), Method: "debug/echo"},
+			{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(11)), Method: "debug/drop"},
+		}
+		responses, err := trans.SendBatch(ctx, requests)
+		if err != nil {
+			t.Fatalf("SendBatch failed: %v", err)
+		}
+		if len(responses) != 2 {
+			t.Fatalf("expected 2 responses, got %v", responses)
+		}
+		if responses[0] == nil {
+			t.Errorf("expected a response for the answered request, got nil")
+		}
+		if responses[1] != nil {
+			t.Errorf("expected nil for the dropped request, got %v", responses[1])
+		}
+	})
+}
+
+func TestInProcessSendBatch(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "0.0.1")
+	srv.RegisterHandler("debug/echo", func(ctx context.Context, params json.RawMessage) (any, error) {
+		var echoed any
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &echoed); err != nil {
+				return nil, err
+			}
+		}
+		return echoed, nil
+	})
+	trans := NewInProcessTransport(srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	requests := []JSONRPCRequest{
+		{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo", Params: map[string]any{"n": float64(1)}},
+		{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(2)), Method: "debug/unknown"},
+	}
+
+	responses, err := trans.SendBatch(ctx, requests)
+	if err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	if responses[0] == nil || responses[0].Error != nil {
+		t.Fatalf("expected a successful echo response, got %+v", responses[0])
+	}
+	var echoed map[string]any
+	if err := json.Unmarshal(responses[0].Result, &echoed); err != nil {
+		t.Fatalf("decode echoed result: %v", err)
+	}
+	if echoed["n"] != float64(1) {
+		t.Errorf("expected echoed n=1, got %v", echoed["n"])
+	}
+	if responses[1] == nil || responses[1].Error == nil {
+		t.Fatalf("expected a method-not-found error for the unregistered method, got %+v", responses[1])
+	}
+}
+
+func TestInProcessSendBatchEmpty(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "0.0.1")
+	trans := NewInProcessTransport(srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	responses, err := trans.SendBatch(ctx, nil)
+	if err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if responses != nil {
+		t.Errorf("expected nil responses for empty batch, got %v", responses)
+	}
+}
@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// BenchmarkStreamableHTTPEcho10MB compares buffering a 10 MB tool result
+// into JSONRPCResponse.Result against streaming it through ResultReader via
+// WithStreamingThreshold, reporting the difference in allocations.
+func BenchmarkStreamableHTTPEcho10MB(b *testing.B) {
+	const size = 10 * 1024 * 1024
+	url, closeF := startMockLargeResultServer(size)
+	defer closeF()
+
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo"}
+
+	b.Run("buffered", func(b *testing.B) {
+		trans, err := NewStreamableHTTP(url)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer trans.Close()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			response, err := trans.SendRequest(context.Background(), request)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(response.Result) == 0 {
+				b.Fatal("expected a non-empty result")
+			}
+		}
+	})
+
+	b.Run("streamed", func(b *testing.B) {
+		trans, err := NewStreamableHTTP(url, WithStreamingThreshold(1<<20))
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer trans.Close()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			response, err := trans.SendRequest(context.Background(), request)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if response.ResultReader == nil {
+				b.Fatal("expected ResultReader to be set")
+			}
+			n, err := io.Copy(io.Discard, response.ResultReader)
+			response.ResultReader.Close()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if n == 0 {
+				b.Fatal("expected a non-empty result")
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// TestInProcessBidirectionalRequest verifies that a server can issue a
+// request back to an in-process client and receive its response, the
+// reverse direction of the usual client-to-server SendRequest.
+func TestInProcessBidirectionalRequest(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "0.0.1")
+	trans := NewInProcessTransport(srv)
+
+	ctx := context.Background()
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer trans.Close()
+
+	trans.RegisterRequestHandler("roots/list", func(ctx context.Context, request JSONRPCRequest) (any, error) {
+		return map[string]any{"roots": []string{"/workspace"}}, nil
+	})
+
+	resultBytes, err := trans.SendRequestToClient(ctx, "roots/list", nil)
+	if err != nil {
+		t.Fatalf("SendRequestToClient failed: %v", err)
+	}
+
+	var result struct {
+		Roots []string `json:"roots"`
+	}
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if len(result.Roots) != 1 || result.Roots[0] != "/workspace" {
+		t.Errorf("unexpected roots: %v", result.Roots)
+	}
+}
+
+// TestInProcessBidirectionalRequestNoHandler verifies that sending a
+// server-initiated request for a method the client has no handler for
+// surfaces as an error rather than hanging.
+func TestInProcessBidirectionalRequestNoHandler(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "0.0.1")
+	trans := NewInProcessTransport(srv)
+
+	ctx := context.Background()
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer trans.Close()
+
+	_, err := trans.SendRequestToClient(ctx, "sampling/createMessage", nil)
+	if err == nil {
+		t.Fatal("expected error for unregistered method, got nil")
+	}
+}
+
+// startMockBidirectionalStreamableHTTPServer starts a mock StreamableHTTP
+// server that, upon receiving a "tools/call" request, pushes a
+// server-initiated "sampling/createMessage" request over the SSE stream,
+// waits for the client to POST back its response, and only then completes
+// the original "tools/call" response.
+func startMockBidirectionalStreamableHTTPServer() (string, func()) {
+	var sessionID string
+	var mu sync.Mutex
+	responses := make(chan map[string]any, 1)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// A message with no "method" field is the client POSTing back the
+		// response to our server-initiated request.
+		if _, isMethodCall := request["method"]; !isMethodCall {
+			responses <- request
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		switch request["method"] {
+		case "initialize":
+			mu.Lock()
+			sessionID = fmt.Sprintf("test-session-%d", time.Now().UnixNano())
+			mu.Unlock()
+			w.Header().Set("Mcp-Session-Id", sessionID)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": request["id"], "result": "initialized"})
+
+		case "tools/call":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+
+			serverRequest := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      999,
+				"method":  "sampling/createMessage",
+				"params":  map[string]any{"prompt": "say hi"},
+			}
+			data, _ := json.Marshal(serverRequest)
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			w.(http.Flusher).Flush()
+
+			select {
+			case clientResponse := <-responses:
+				result, _ := clientResponse["result"].(map[string]any)
+				response := map[string]any{
+					"jsonrpc": "2.0",
+					"id":      request["id"],
+					"result":  result,
+				}
+				responseData, _ := json.Marshal(response)
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", responseData)
+			case <-time.After(5 * time.Second):
+				http.Error(w, "timed out waiting for client response", http.StatusGatewayTimeout)
+			}
+		}
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, testServer.Close
+}
+
+// TestStreamableHTTPBidirectionalRequest verifies that a server-initiated
+// request arriving on the SSE stream for an in-flight SendRequest call is
+// handled by the client's registered handler and POSTed back, completing
+// the original call's round trip.
+func TestStreamableHTTPBidirectionalRequest(t *testing.T) {
+	url, closeF := startMockBidirectionalStreamableHTTPServer()
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := trans.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewRequestId(int64(0)),
+		Method:  "initialize",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	trans.RegisterRequestHandler("sampling/createMessage", func(ctx context.Context, request JSONRPCRequest) (any, error) {
+		return map[string]any{"text": "hi"}, nil
+	})
+
+	response, err := trans.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "tools/call",
+	})
+	if err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if result.Text != "hi" {
+		t.Errorf("expected text 'hi', got %q", result.Text)
+	}
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// startMockSlowServer starts a server that accepts a POSTed request and
+// never answers it, so the client can cancel the call mid-flight; any
+// notifications/cancelled notification it receives afterward is delivered
+// on the returned channel.
+func startMockSlowServer() (string, <-chan map[string]any, func()) {
+	cancelled := make(chan map[string]any, 1)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if message["method"] == "notifications/cancelled" {
+			cancelled <- message
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		// An ordinary request: accept it, but never respond, simulating
+		// work that is still in flight when the client gives up.
+		<-r.Context().Done()
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, cancelled, testServer.Close
+}
+
+func TestStreamableHTTPSendRequestCancellation(t *testing.T) {
+	url, cancelled, closeF := startMockSlowServer()
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo"}
+	_, err = trans.SendRequest(ctx, request)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case message := <-cancelled:
+		if message["jsonrpc"] != "2.0" {
+			t.Errorf("expected jsonrpc 2.0, got %v", message["jsonrpc"])
+		}
+		params, ok := message["params"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected params object, got %v", message["params"])
+		}
+		if got := params["requestId"]; got != float64(1) {
+			t.Errorf("expected requestId 1, got %v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server never observed a notifications/cancelled message")
+	}
+}
+
+func TestStreamableHTTPRequestTimeoutOption(t *testing.T) {
+	url, cancelled, closeF := startMockSlowServer()
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url, WithRequestTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo"}
+	_, err = trans.SendRequest(context.Background(), request)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded from WithRequestTimeout, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server never observed a notifications/cancelled message")
+	}
+}
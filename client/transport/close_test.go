@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// startMockOpenSSEServer starts an SSE server that sends one event, then
+// holds the connection open indefinitely instead of returning — the normal
+// steady state for a long-lived listen stream that pushes events "at any
+// time", as opposed to startMockResumableSSEServer's simulated drop.
+func startMockOpenSSEServer() (string, func()) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		w.Write([]byte("id: 1\nevent: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notify/a\",\"params\":{}}\n\n"))
+		flusher.Flush()
+
+		<-r.Context().Done()
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, testServer.Close
+}
+
+// TestStreamableHTTPCloseUnblocksOpenListenStream proves Close returns
+// promptly even while runListenLoop is blocked reading from a listen stream
+// the server is deliberately keeping open, rather than hanging until the
+// server eventually closes the connection.
+func TestStreamableHTTPCloseUnblocksOpenListenStream(t *testing.T) {
+	url, closeF := startMockOpenSSEServer()
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := trans.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- trans.Close() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Close returned an error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return while the listen stream's read was blocked")
+	}
+}
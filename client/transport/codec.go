@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// Codec controls how a transport turns JSON-RPC messages into bytes on the
+// wire and back. The default, used when a transport is not given one via
+// WithCodec, is backed by encoding/json. Callers that need a
+// json.RawMessage-preserving codec, or want to swap in a faster drop-in
+// replacement such as jsoniter or segmentio/encoding/json, can implement
+// this interface themselves and pass it to WithCodec.
+type Codec interface {
+	// Encode writes v to w in whatever wire format the codec uses.
+	Encode(w io.Writer, v any) error
+
+	// Decode reads a single value from r into v.
+	Decode(r io.Reader, v any) error
+}
+
+// jsonCodec is the default Codec, implemented with encoding/json.
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// marshal encodes v using codec, or the default jsonCodec when codec is
+// nil, and returns the resulting bytes. It exists because most call sites
+// in this package need a []byte to put on an http.Request or hand to a
+// lower transport, while Codec itself only deals in io.Writer.
+func marshal(codec Codec, v any) ([]byte, error) {
+	if codec == nil {
+		codec = jsonCodec{}
+	}
+	var buf bytes.Buffer
+	if err := codec.Encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshal decodes data into v using codec, or the default jsonCodec when
+// codec is nil.
+func unmarshal(codec Codec, data []byte, v any) error {
+	if codec == nil {
+		codec = jsonCodec{}
+	}
+	return codec.Decode(bytes.NewReader(data), v)
+}
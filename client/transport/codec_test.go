@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// countingCodec wraps jsonCodec and counts how many times Encode/Decode are
+// invoked, so tests can assert a transport actually used the Codec it was
+// given instead of falling back to encoding/json directly.
+type countingCodec struct {
+	encodes int
+	decodes int
+}
+
+func (c *countingCodec) Encode(w io.Writer, v any) error {
+	c.encodes++
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (c *countingCodec) Decode(r io.Reader, v any) error {
+	c.decodes++
+	return json.NewDecoder(r).Decode(v)
+}
+
+func TestWithCodecIsUsedByStreamableHTTP(t *testing.T) {
+	url, closeF := startMockStreamableHTTPServer()
+	defer closeF()
+
+	codec := &countingCodec{}
+	trans, err := NewStreamableHTTP(url, WithCodec(codec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx := context.Background()
+	if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(0)), Method: "initialize"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if codec.encodes == 0 {
+		t.Error("expected the custom codec's Encode to be used for outgoing requests")
+	}
+	if codec.decodes == 0 {
+		t.Error("expected the custom codec's Decode to be used for incoming responses")
+	}
+}
+
+func TestMarshalUnmarshalDefaultCodec(t *testing.T) {
+	data, err := marshal(nil, map[string]any{"a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out map[string]any
+	if err := unmarshal(nil, data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["a"].(float64) != 1 {
+		t.Errorf("expected a=1, got %v", out["a"])
+	}
+}
+
+var errEncode = errors.New("boom")
+
+type errCodec struct{}
+
+func (errCodec) Encode(io.Writer, any) error { return errEncode }
+func (errCodec) Decode(io.Reader, any) error { return errEncode }
+
+func TestMarshalPropagatesEncodeError(t *testing.T) {
+	_, err := marshal(errCodec{}, "anything")
+	if !errors.Is(err, errEncode) {
+		t.Fatalf("expected errEncode, got %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// InProcessTransport connects a client directly to a server.MCPServer
+// running in the same process, skipping serialization entirely. It also
+// acts as the server.ClientSession for that server, so the server can issue
+// requests back to the client (sampling/createMessage, roots/list, ...) the
+// same way it would over any other transport.
+type InProcessTransport struct {
+	server    *server.MCPServer
+	sessionID string
+
+	mu                  sync.RWMutex
+	notificationHandler func(mcp.JSONRPCNotification)
+	requestHandlers     map[string]RequestHandlerFunc
+
+	notifCh chan mcp.JSONRPCNotification
+
+	nextRequestID int64
+
+	// codec controls how messages are (de)serialized between the client
+	// and srv. It is never nil: NewInProcessTransport falls back to
+	// jsonCodec when no WithInProcessCodec option is given.
+	codec Codec
+}
+
+// inProcessSessionCounter gives every InProcessTransport a unique session
+// ID. It must not be derived from a pointer to a zero-size value: Go's
+// runtime allocates those at a single shared address ("zerobase"), so
+// &struct{}{} is identical across calls and would collide in
+// server.MCPServer's session map.
+var inProcessSessionCounter int64
+
+// NewInProcessTransport creates a transport wired directly to srv.
+func NewInProcessTransport(srv *server.MCPServer, opts ...InProcessTransportOption) *InProcessTransport {
+	id := atomic.AddInt64(&inProcessSessionCounter, 1)
+	t := &InProcessTransport{
+		server:          srv,
+		sessionID:       fmt.Sprintf("in-process-%d", id),
+		requestHandlers: make(map[string]RequestHandlerFunc),
+		notifCh:         make(chan mcp.JSONRPCNotification, 100),
+		codec:           jsonCodec{},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// InProcessTransportOption configures an InProcessTransport.
+type InProcessTransportOption func(*InProcessTransport)
+
+// WithInProcessCodec sets the Codec used to (de)serialize every message
+// this transport exchanges with its server, in place of the default
+// encoding/json-backed one.
+func WithInProcessCodec(codec Codec) InProcessTransportOption {
+	return func(t *InProcessTransport) {
+		t.codec = codec
+	}
+}
+
+// Start registers this transport as a session with the server and begins
+// forwarding the server's notifications to the client's notification
+// handler.
+func (t *InProcessTransport) Start(ctx context.Context) error {
+	t.server.RegisterSession(t)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification, ok := <-t.notifCh:
+				if !ok {
+					return
+				}
+				t.mu.RLock()
+				handler := t.notificationHandler
+				t.mu.RUnlock()
+				if handler != nil {
+					handler(notification)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// SendRequest delivers request directly to the server and returns its
+// response.
+func (t *InProcessTransport) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	raw, err := marshal(t.codec, request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	result := t.server.HandleMessage(ctx, t, raw)
+	if result == nil {
+		return nil, nil
+	}
+
+	resultBytes, err := marshal(t.codec, result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal server response: %w", err)
+	}
+
+	var response JSONRPCResponse
+	if err := unmarshal(t.codec, resultBytes, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal server response: %w", err)
+	}
+	return &response, nil
+}
+
+// SendRequestStreaming behaves like SendRequest, except that the server's
+// response is encoded on one end of an io.Pipe while this transport decodes
+// its envelope from the other end, so the two never both hold a complete
+// copy of a large result in memory at once. Once the result's value turns
+// out to be at least threshold bytes, it is left out of the returned
+// response's Result and handed back via ResultReader instead, which the
+// caller must close. threshold <= 0 disables streaming, behaving exactly
+// like SendRequest.
+func (t *InProcessTransport) SendRequestStreaming(ctx context.Context, request JSONRPCRequest, threshold int64) (*JSONRPCResponse, error) {
+	if threshold <= 0 {
+		return t.SendRequest(ctx, request)
+	}
+
+	raw, err := marshal(t.codec, request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	result := t.server.HandleMessage(ctx, t, raw)
+	if result == nil {
+		return nil, nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(t.codec.Encode(pw, result))
+	}()
+
+	return decodeStreamingEnvelope(pr, threshold)
+}
+
+// SendBatch delivers requests to the server as a single JSON-RPC batch and
+// returns their responses in requests' order.
+func (t *InProcessTransport) SendBatch(ctx context.Context, requests []JSONRPCRequest) ([]*JSONRPCResponse, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	raw, err := marshal(t.codec, requests)
+	if err != nil {
+		return nil, fmt.Errorf("marshal batch: %w", err)
+	}
+
+	result := t.server.HandleMessage(ctx, t, raw)
+	if result == nil {
+		return nil, nil
+	}
+
+	resultBytes, err := marshal(t.codec, result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal server batch response: %w", err)
+	}
+
+	var responses []*JSONRPCResponse
+	if err := unmarshal(t.codec, resultBytes, &responses); err != nil {
+		return nil, fmt.Errorf("unmarshal server batch response: %w", err)
+	}
+	return orderResponsesByRequest(requests, responses), nil
+}
+
+// SendNotification delivers a notification directly to the server.
+func (t *InProcessTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	raw, err := marshal(t.codec, notification)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+	t.server.HandleMessage(ctx, t, raw)
+	return nil
+}
+
+// SetNotificationHandler sets the handler for notifications sent by the
+// server to this client.
+func (t *InProcessTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notificationHandler = handler
+}
+
+// RegisterRequestHandler registers the handler invoked when the server
+// issues a request to this client.
+func (t *InProcessTransport) RegisterRequestHandler(method string, handler RequestHandlerFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requestHandlers[method] = handler
+}
+
+// Close unregisters this transport's session from the server.
+func (t *InProcessTransport) Close() error {
+	t.server.UnregisterSession(t.sessionID)
+	t.mu.Lock()
+	close(t.notifCh)
+	t.mu.Unlock()
+	return nil
+}
+
+// --- server.ClientSession ---
+
+// SessionID returns the session identifier the server knows this transport
+// by.
+func (t *InProcessTransport) SessionID() string {
+	return t.sessionID
+}
+
+// NotificationChannel returns the channel the server should send
+// notifications for this session to.
+func (t *InProcessTransport) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return t.notifCh
+}
+
+// Initialized always reports true: an in-process transport has no separate
+// handshake step to wait for.
+func (t *InProcessTransport) Initialized() bool {
+	return true
+}
+
+// --- server.RequestCapableSession ---
+
+// SendRequestToClient is called by the server (via
+// MCPServer.SendRequestToClient) to issue method/params to the client and
+// wait for its response. The registered handler runs in its own goroutine
+// with a context derived from ctx, so that canceling ctx (e.g. because the
+// client disconnected) aborts the handler without blocking the server.
+func (t *InProcessTransport) SendRequestToClient(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	t.mu.RLock()
+	handler, ok := t.requestHandlers[method]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("client has no handler registered for method %q", method)
+	}
+
+	id := atomic.AddInt64(&t.nextRequestID, 1)
+	request := JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(id),
+		Method:  method,
+		Params:  params,
+	}
+
+	handlerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := handler(handlerCtx, request)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case out := <-done:
+		if out.err != nil {
+			return nil, out.err
+		}
+		return json.Marshal(out.result)
+	}
+}
@@ -0,0 +1,23 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// TestNewInProcessTransportUniqueSessionID guards against a regression where
+// every InProcessTransport shared the same session ID (derived from the
+// address of a freshly allocated zero-size value, which Go's runtime gives
+// the same address every time), causing one transport's Close to delete
+// another's entry from the server's session map.
+func TestNewInProcessTransportUniqueSessionID(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.0.0")
+
+	t1 := NewInProcessTransport(srv)
+	t2 := NewInProcessTransport(srv)
+
+	if t1.SessionID() == t2.SessionID() {
+		t.Fatalf("expected distinct session IDs, got %q for both", t1.SessionID())
+	}
+}
@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// RequestHandlerFunc handles a request sent by the peer at the other end of
+// a transport (for example a server calling back into a client to issue a
+// sampling/createMessage request). It is invoked with a context scoped to
+// the lifetime of that single request; canceling it (e.g. because the
+// underlying connection died) should abort whatever work the handler is
+// doing.
+type RequestHandlerFunc func(ctx context.Context, request JSONRPCRequest) (any, error)
+
+// Interface is implemented by the transports that carry MCP's JSON-RPC
+// traffic: in-process, stdio, StreamableHTTP, etc. A transport is
+// responsible for framing and delivering messages; dispatch of what a
+// message means is left to the client or server sitting on top of it.
+type Interface interface {
+	// Start the connection. Start should only be called once.
+	Start(ctx context.Context) error
+
+	// SendRequest sends a JSON-RPC request and waits for a response, or
+	// until the context is canceled.
+	SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error)
+
+	// SendBatch sends requests together as a single JSON-RPC 2.0 §6 batch
+	// and waits for all of their responses. The returned slice has the same
+	// length and order as requests; per-element errors are surfaced via
+	// that response's Error field rather than failing the whole call. An
+	// empty requests slice is a no-op returning a nil slice.
+	SendBatch(ctx context.Context, requests []JSONRPCRequest) ([]*JSONRPCResponse, error)
+
+	// SendNotification sends a JSON-RPC notification to the peer. No
+	// response is expected.
+	SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error
+
+	// SetNotificationHandler sets the handler invoked for every
+	// notification received from the peer. Only one handler is active at a
+	// time; a second call replaces the first.
+	SetNotificationHandler(handler func(notification mcp.JSONRPCNotification))
+
+	// RegisterRequestHandler registers the handler invoked when the peer at
+	// the other end of the transport issues a request back to us (e.g. a
+	// server-initiated "roots/list" received by a client). Registering a
+	// handler for a method that already has one replaces it.
+	RegisterRequestHandler(method string, handler RequestHandlerFunc)
+
+	// Close the connection.
+	Close() error
+}
+
+// StreamingSender is implemented by transports that can avoid buffering a
+// large result into memory by handing it back through
+// JSONRPCResponse.ResultReader instead of JSONRPCResponse.Result. Not every
+// transport supports this (StreamableHTTP and InProcessTransport do);
+// callers should type-assert for it and fall back to Interface.SendRequest
+// when it's absent, as Client.SendRequestStreaming does.
+type StreamingSender interface {
+	// SendRequestStreaming behaves like Interface.SendRequest, except that
+	// once a response's result is at least threshold bytes, it is left out
+	// of the returned JSONRPCResponse.Result and handed back via
+	// JSONRPCResponse.ResultReader instead, which the caller must close
+	// once done with it. threshold <= 0 disables streaming for this call,
+	// behaving exactly like SendRequest.
+	SendRequestStreaming(ctx context.Context, request JSONRPCRequest, threshold int64) (*JSONRPCResponse, error)
+}
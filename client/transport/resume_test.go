@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startMockHeldOpenSSEServer starts an SSE server that sends one event per
+// connection and then holds that connection open (like
+// startMockOpenSSEServer) instead of returning, so whether a given
+// connection is still open can only change once something cancels its
+// request context. openCount reports how many connections are currently
+// open at once, which is how the test below distinguishes "the old loop
+// was stopped" from "the old loop is still running alongside a new one".
+func startMockHeldOpenSSEServer() (string, func(), func() int32) {
+	var open int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		atomic.AddInt32(&open, 1)
+		defer atomic.AddInt32(&open, -1)
+
+		fmt.Fprintf(w, "id: 1\nevent: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notify/a\",\"params\":{}}\n\n")
+		flusher.Flush()
+
+		<-r.Context().Done()
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, testServer.Close, func() int32 { return atomic.LoadInt32(&open) }
+}
+
+// TestStreamableHTTPResumeReplacesRunningLoop proves that calling Resume
+// stops the listen stream already running (started by Start here) instead
+// of leaving it open alongside the new one Resume opens — which would
+// otherwise leave two loops independently advancing lastEventID and
+// dispatching every subsequent push twice.
+func TestStreamableHTTPResumeReplacesRunningLoop(t *testing.T) {
+	url, closeF, openCount := startMockHeldOpenSSEServer()
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for openCount() < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if openCount() != 1 {
+		t.Fatalf("expected the initial listen stream to be open, got openCount=%d", openCount())
+	}
+
+	if err := trans.Resume(ctx); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	// Give Resume's new connection time to land, and the old one time to be
+	// torn down if Resume stopped it as it should.
+	deadline = time.Now().Add(2 * time.Second)
+	for openCount() > 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := openCount(); got != 1 {
+		t.Errorf("expected exactly one listen stream open after Resume, got %d (Resume left the old loop running?)", got)
+	}
+}
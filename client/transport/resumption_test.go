@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// startMockResumableSSEServer starts a bare SSE server that drops its first
+// connection immediately after sending one event, to exercise a client's
+// Last-Event-ID based resumption. The second connection asserts it received
+// the Last-Event-ID the first one sent, then delivers one more event the
+// client hasn't seen yet.
+func startMockResumableSSEServer(t *testing.T) (string, func()) {
+	var connCount int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		switch n := atomic.AddInt32(&connCount, 1); n {
+		case 1:
+			fmt.Fprintf(w, "id: 1\nevent: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notify/a","params":{}}`)
+			flusher.Flush()
+			// Simulate a dropped connection: return without closing the
+			// stream any more gracefully than a network failure would.
+
+		default:
+			if got := r.Header.Get("Last-Event-ID"); got != "1" {
+				t.Errorf("resumed connection: expected Last-Event-ID %q, got %q", "1", got)
+			}
+			fmt.Fprintf(w, "id: 2\nevent: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notify/b","params":{}}`)
+			flusher.Flush()
+		}
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, testServer.Close
+}
+
+func TestStreamableHTTPResumption(t *testing.T) {
+	url, closeF := startMockResumableSSEServer(t)
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	var mu sync.Mutex
+	var received []string
+	trans.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, notification.Method)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	deadline := time.Now().Add(4 * time.Second)
+	for {
+		mu.Lock()
+		got := len(received)
+		mu.Unlock()
+		if got >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"notify/a", "notify/b"}
+	if len(received) != len(want) {
+		t.Fatalf("expected notifications %v, got %v", want, received)
+	}
+	for i, method := range want {
+		if received[i] != method {
+			t.Errorf("notification %d: expected %q, got %q", i, method, received[i])
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package transport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// decodeStreamingEnvelope decodes body's JSON-RPC envelope field by field,
+// the same way StreamableHTTP.decodeStreamingResult does, but without a
+// Content-Length to tell upfront whether "result" is worth streaming: it
+// peeks up to threshold+1 bytes of result's value and, if that's already
+// everything there is, decides it wasn't worth streaming and decodes it
+// into Result directly; otherwise it hands back a reader bounded to the
+// value via ResultReader. body is closed once its envelope (and, when not
+// streaming, its result) has been fully consumed, or on any error.
+func decodeStreamingEnvelope(body io.ReadCloser, threshold int64) (*JSONRPCResponse, error) {
+	peekSize := int(threshold) + 1
+	br := bufio.NewReaderSize(body, peekSize+64)
+	dec := json.NewDecoder(br)
+
+	tok, err := dec.Token()
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		body.Close()
+		return nil, fmt.Errorf("decode response: expected a JSON object")
+	}
+
+	var response JSONRPCResponse
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			body.Close()
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		key, _ := keyTok.(string)
+
+		if key == "result" {
+			rest := bufio.NewReaderSize(io.MultiReader(dec.Buffered(), br), peekSize+64)
+			if err := skipJSONColon(rest); err != nil {
+				body.Close()
+				return nil, fmt.Errorf("decode response: %w", err)
+			}
+			peeked, _ := rest.Peek(peekSize)
+			if len(peeked) < peekSize {
+				// Everything result (and the envelope's closing brace)
+				// will ever have is already in hand: not worth streaming.
+				var raw json.RawMessage
+				if err := json.NewDecoder(rest).Decode(&raw); err != nil {
+					body.Close()
+					return nil, fmt.Errorf("decode response: %w", err)
+				}
+				response.Result = raw
+				body.Close()
+				return &response, nil
+			}
+			response.ResultReader = &streamedResult{Reader: newJSONValueReader(rest), body: body}
+			return &response, nil
+		}
+
+		var target any
+		switch key {
+		case "jsonrpc":
+			target = &response.JSONRPC
+		case "id":
+			target = &response.ID
+		case "error":
+			target = &response.Error
+		default:
+			var discard json.RawMessage
+			target = &discard
+		}
+		if err := dec.Decode(target); err != nil {
+			body.Close()
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+
+	body.Close()
+	return &response, nil
+}
+
+// streamedResult wraps a reader bounded to a JSON-RPC response's "result"
+// value together with the underlying connection it was read from, so that
+// closing it (whether or not the value was read to completion) releases
+// that connection.
+type streamedResult struct {
+	io.Reader
+	body io.Closer
+}
+
+func (s *streamedResult) Close() error {
+	return s.body.Close()
+}
+
+// jsonValueReader copies exactly the bytes of the next complete JSON value
+// available from br, then reports io.EOF, leaving whatever comes after that
+// value (a closing brace, further fields, ...) unread in br. It is used to
+// hand a response's "result" field to a caller as a stream instead of
+// buffering it into a json.RawMessage.
+type jsonValueReader struct {
+	br      *bufio.Reader
+	started bool
+	done    bool
+	depth   int
+	inStr   bool
+	escape  bool
+}
+
+func newJSONValueReader(br *bufio.Reader) *jsonValueReader {
+	return &jsonValueReader{br: br}
+}
+
+// fillStringRun copies as much of the current JSON string's content into p
+// as it can in one go, scanning a peeked chunk for the closing quote (or an
+// escape immediately preceding it) instead of consuming br one byte at a
+// time, since a large result is typically a single long string. It reports
+// how much of p it filled and whether it copied the closing quote.
+func (r *jsonValueReader) fillStringRun(p []byte) (consumed int, closed bool, err error) {
+	chunk, peekErr := r.br.Peek(len(p))
+	if len(chunk) == 0 {
+		if peekErr != nil {
+			return 0, false, peekErr
+		}
+		return 0, false, nil
+	}
+
+	end := len(chunk)
+	for i, b := range chunk {
+		if r.escape {
+			r.escape = false
+			continue
+		}
+		if b == '\\' {
+			r.escape = true
+			continue
+		}
+		if b == '"' {
+			r.inStr = false
+			end = i + 1
+			closed = true
+			break
+		}
+	}
+
+	n := copy(p, chunk[:end])
+	if _, err := r.br.Discard(n); err != nil {
+		return n, closed, err
+	}
+	return n, closed, nil
+}
+
+func (r *jsonValueReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) {
+		if r.inStr {
+			consumed, closed, err := r.fillStringRun(p[n:])
+			n += consumed
+			if closed && r.depth == 0 {
+				r.done = true
+				return n, nil
+			}
+			if err != nil {
+				r.done = true
+				if n > 0 {
+					return n, nil
+				}
+				return 0, err
+			}
+			continue
+		}
+
+		b, err := r.br.ReadByte()
+		if err != nil {
+			r.done = true
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		switch {
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if !r.started {
+				// Leading whitespace before the value starts: skip it
+				// without copying it to p.
+				continue
+			}
+		case b == '"':
+			r.started = true
+			r.inStr = true
+		case b == '{' || b == '[':
+			r.started = true
+			r.depth++
+		case b == '}' || b == ']':
+			r.depth--
+			p[n] = b
+			n++
+			if r.started && r.depth == 0 {
+				r.done = true
+				return n, nil
+			}
+			continue
+		default:
+			// A bare literal (number, true, false, null): it ends at the
+			// next delimiter, which itself belongs to whatever comes after
+			// the value, so it is peeked rather than consumed.
+			r.started = true
+			if r.depth == 0 {
+				p[n] = b
+				n++
+				if next, err := r.br.Peek(1); err != nil || isJSONValueDelimiter(next[0]) {
+					r.done = true
+					return n, nil
+				}
+				continue
+			}
+		}
+
+		p[n] = b
+		n++
+	}
+	return n, nil
+}
+
+// skipJSONColon consumes the ':' separating an object key from its value
+// (and any insignificant whitespace around it) from br. It exists because
+// json.Decoder.Token only returns the key itself; the colon is left for
+// whatever reads the value next, which here is jsonValueReader rather than
+// the Decoder.
+func skipJSONColon(br *bufio.Reader) error {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return nil
+		default:
+			return fmt.Errorf("expected ':' after object key, got %q", b)
+		}
+	}
+}
+
+func isJSONValueDelimiter(b byte) bool {
+	switch b {
+	case ',', '}', ']', ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
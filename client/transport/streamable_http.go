@@ -0,0 +1,801 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+const (
+	listenInitialBackoff = 500 * time.Millisecond
+	listenMaxBackoff     = 30 * time.Second
+
+	// cancelNotificationTimeout bounds how long SendRequest waits to deliver
+	// a notifications/cancelled message once its own context is already
+	// done; it uses an independent context since ctx itself cannot be used
+	// any more.
+	cancelNotificationTimeout = 5 * time.Second
+)
+
+// StreamableHTTP implements Interface over the MCP "Streamable HTTP"
+// transport: every client-to-server message is a POST to a single endpoint,
+// whose response is either a single JSON-RPC reply or a
+// "text/event-stream" carrying zero or more notifications/requests followed
+// by the reply. A server may also push messages to the client at any time
+// over a long-lived GET SSE stream started by Start.
+type StreamableHTTP struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+
+	sessionMu sync.RWMutex
+	sessionID string
+
+	// lastEventID is the ID of the most recent SSE event this transport has
+	// processed, per the "id:" field described by the SSE spec. It lets a
+	// dropped listen stream be resumed from where it left off instead of
+	// from the beginning: the server is expected to replay anything it
+	// buffered after this ID when it sees it on a Last-Event-ID header.
+	lastEventMu sync.RWMutex
+	lastEventID string
+
+	// requestTimeout, if set, bounds how long SendRequest waits for a
+	// response when ctx does not already carry a deadline of its own.
+	requestTimeout time.Duration
+
+	// codec controls how messages are (de)serialized on the wire. It is
+	// never nil: NewStreamableHTTP falls back to jsonCodec when no
+	// WithCodec option is given.
+	codec Codec
+
+	// streamingThreshold, if set, makes SendRequest hand back a response's
+	// result as a ResultReader instead of buffering it into Result once the
+	// HTTP response body reaches this many bytes. See WithStreamingThreshold.
+	streamingThreshold int64
+
+	notifMu             sync.RWMutex
+	notificationHandler func(notification mcp.JSONRPCNotification)
+
+	reqHandlersMu   sync.RWMutex
+	requestHandlers map[string]RequestHandlerFunc
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+
+	// listenMu guards listenCancel, which aborts the current listen stream's
+	// request (including any read blocked inside it) so Close does not have
+	// to wait for the server to close the connection on its own.
+	listenMu     sync.Mutex
+	listenCancel context.CancelFunc
+}
+
+// NewStreamableHTTP creates a StreamableHTTP transport targeting rawURL.
+func NewStreamableHTTP(rawURL string, opts ...StreamableHTTPOption) (*StreamableHTTP, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	s := &StreamableHTTP{
+		baseURL:         u,
+		httpClient:      &http.Client{},
+		requestHandlers: make(map[string]RequestHandlerFunc),
+		closeCh:         make(chan struct{}),
+		codec:           jsonCodec{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// StreamableHTTPOption configures a StreamableHTTP transport.
+type StreamableHTTPOption func(*StreamableHTTP)
+
+// WithRequestTimeout bounds how long SendRequest waits for a response when
+// the caller's context doesn't already carry a deadline. It has no effect
+// on a call whose context already has one, and no effect on the long-lived
+// listen stream started by Start.
+func WithRequestTimeout(d time.Duration) StreamableHTTPOption {
+	return func(s *StreamableHTTP) {
+		s.requestTimeout = d
+	}
+}
+
+// WithCodec sets the Codec used to (de)serialize every message this
+// transport sends or receives, in place of the default encoding/json-backed
+// one. This is the hook for swapping in a json.RawMessage-preserving codec,
+// jsoniter, segmentio/encoding/json, or similar drop-in replacements.
+func WithCodec(codec Codec) StreamableHTTPOption {
+	return func(s *StreamableHTTP) {
+		s.codec = codec
+	}
+}
+
+// WithStreamingThreshold makes SendRequest avoid buffering a response's
+// result into memory once the HTTP response body reaches n bytes, handing
+// it back as JSONRPCResponse.ResultReader instead of JSONRPCResponse.Result
+// — useful for tool results that read back multi-MB blobs (e.g. a resource
+// read). It only applies to a single JSON-RPC reply whose Content-Length is
+// known and at least n; SSE replies and bodies of unknown length are always
+// buffered as before. The default, zero, disables streaming entirely, as
+// does passing n <= 0 to SendRequestStreaming for a single call; a
+// SendRequestStreaming call with n > 0 overrides this default instead of
+// requiring it to be configured up front.
+func WithStreamingThreshold(n int64) StreamableHTTPOption {
+	return func(s *StreamableHTTP) {
+		s.streamingThreshold = n
+	}
+}
+
+// WithHTTPTimeouts configures the underlying http.Transport's connection
+// setup timeouts. These bound how long establishing a connection can take;
+// they do not bound how long a request's body (including a long-lived SSE
+// stream) may then take to be read, which is what WithRequestTimeout and a
+// caller's context are for.
+func WithHTTPTimeouts(dial, tlsHandshake, responseHeader time.Duration) StreamableHTTPOption {
+	return func(s *StreamableHTTP) {
+		s.httpClient.Transport = &http.Transport{
+			DialContext:           (&net.Dialer{Timeout: dial}).DialContext,
+			TLSHandshakeTimeout:   tlsHandshake,
+			ResponseHeaderTimeout: responseHeader,
+		}
+	}
+}
+
+// Start opens a long-lived GET SSE stream so the server can push
+// notifications and server-initiated requests to this client outside the
+// context of any single SendRequest call. It is safe to call SendRequest
+// without ever calling Start; Start is only needed to receive unsolicited
+// server pushes.
+//
+// If the stream is later dropped by a transient network error or a non-2xx
+// response, the transport reconnects automatically, sending the
+// Last-Event-ID of the last event it saw so the server can replay whatever
+// was emitted while it was gone; callers do not need to call Start again.
+// Resume does the same thing on demand.
+func (s *StreamableHTTP) Start(ctx context.Context) error {
+	return s.startListen(ctx)
+}
+
+// Resume reopens the listen stream from the last Last-Event-ID this
+// transport has seen. It behaves like Start, and exists for callers that
+// want to force a reconnect explicitly rather than waiting on the
+// transport's own automatic retry; any listen loop already running
+// (including one from a prior Start or automatic reconnect) is stopped
+// first so the two never run concurrently.
+func (s *StreamableHTTP) Resume(ctx context.Context) error {
+	return s.startListen(ctx)
+}
+
+// startListen opens a new listen stream and starts runListenLoop over it,
+// first canceling whichever listen loop is currently running (if any) so
+// Start/Resume never leave two loops advancing lastEventID and dispatching
+// pushes concurrently.
+func (s *StreamableHTTP) startListen(ctx context.Context) error {
+	listenCtx, cancel := context.WithCancel(ctx)
+
+	resp, err := s.openListenStream(listenCtx, s.getLastEventID())
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	s.listenMu.Lock()
+	if s.listenCancel != nil {
+		s.listenCancel()
+	}
+	s.listenCancel = cancel
+	s.listenMu.Unlock()
+
+	s.wg.Add(1)
+	go s.runListenLoop(listenCtx, resp)
+
+	return nil
+}
+
+func (s *StreamableHTTP) openListenStream(ctx context.Context, lastEventID string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build listen request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if sid := s.getSessionID(); sid != "" {
+		req.Header.Set("Mcp-Session-Id", sid)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("open listen stream: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("open listen stream: status %d: %s", resp.StatusCode, string(data))
+	}
+	return resp, nil
+}
+
+// runListenLoop reads SSE events from resp until the stream ends, then
+// reconnects with exponential backoff, carrying forward whatever
+// Last-Event-ID was last recorded, until the transport is closed or ctx is
+// done.
+func (s *StreamableHTTP) runListenLoop(ctx context.Context, resp *http.Response) {
+	defer s.wg.Done()
+
+	backoff := listenInitialBackoff
+	for {
+		// The return value is ignored: this is a background listener with
+		// no in-flight request of its own to satisfy.
+		_, _ = s.readEvents(ctx, resp.Body, nil)
+		resp.Body.Close()
+
+		select {
+		case <-s.closeCh:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		select {
+		case <-s.closeCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		next, err := s.openListenStream(ctx, s.getLastEventID())
+		if err != nil {
+			backoff = nextListenBackoff(backoff)
+			continue
+		}
+		resp = next
+		backoff = listenInitialBackoff
+	}
+}
+
+func nextListenBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > listenMaxBackoff {
+		return listenMaxBackoff
+	}
+	return backoff
+}
+
+// SendRequest sends request to the server and waits for its response. If
+// ctx is canceled or times out before a response arrives, SendRequest
+// aborts the underlying HTTP call, sends a notifications/cancelled message
+// carrying request's ID so the server can abort whatever work it was
+// doing, and returns ctx.Err().
+func (s *StreamableHTTP) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	return s.sendAndWait(ctx, request, func(ctx context.Context) (*JSONRPCResponse, error) {
+		return s.doSendRequest(ctx, request, s.streamingThreshold)
+	})
+}
+
+// SendRequestStreaming behaves exactly like SendRequest, except that
+// threshold overrides the transport's configured WithStreamingThreshold for
+// this call only; threshold <= 0 disables streaming for this call,
+// behaving exactly like SendRequest with no threshold configured. It
+// implements StreamingSender.
+func (s *StreamableHTTP) SendRequestStreaming(ctx context.Context, request JSONRPCRequest, threshold int64) (*JSONRPCResponse, error) {
+	return s.sendAndWait(ctx, request, func(ctx context.Context) (*JSONRPCResponse, error) {
+		return s.doSendRequest(ctx, request, threshold)
+	})
+}
+
+// sendAndWait runs do in its own goroutine and waits for either it to
+// finish or ctx to be canceled first, in which case it sends a
+// notifications/cancelled message for request's ID before returning
+// ctx.Err(). It is the shared cancellation plumbing behind SendRequest and
+// SendRequestStreaming.
+func (s *StreamableHTTP) sendAndWait(ctx context.Context, request JSONRPCRequest, do func(context.Context) (*JSONRPCResponse, error)) (*JSONRPCResponse, error) {
+	ctx, cancel := s.withRequestTimeout(ctx)
+	defer cancel()
+
+	type result struct {
+		response *JSONRPCResponse
+		err      error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		response, err := do(ctx)
+		done <- result{response, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-ctx.Done():
+		s.notifyCancelled(request.ID)
+		return nil, ctx.Err()
+	}
+}
+
+// withRequestTimeout applies s.requestTimeout to ctx if it is set and ctx
+// does not already carry a deadline of its own.
+func (s *StreamableHTTP) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.requestTimeout)
+}
+
+// notifyCancelled tells the server that the request with the given ID has
+// been abandoned client-side, using a fresh context since the one that
+// triggered the cancellation is already done.
+func (s *StreamableHTTP) notifyCancelled(id mcp.RequestId) {
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: "notifications/cancelled",
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{
+					"requestId": id.Value(),
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelNotificationTimeout)
+	defer cancel()
+	_ = s.SendNotification(ctx, notification)
+}
+
+// doSendRequest performs the actual POST and waits for request's response,
+// without any timeout or cancellation-notification handling of its own;
+// SendRequest wraps it with that.
+func (s *StreamableHTTP) doSendRequest(ctx context.Context, request JSONRPCRequest, streamingThreshold int64) (*JSONRPCResponse, error) {
+	body, err := marshal(s.codec, request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	if sid := s.getSessionID(); sid != "" {
+		httpReq.Header.Set("Mcp-Session-Id", sid)
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		s.setSessionID(sid)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(data))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		defer resp.Body.Close()
+		response, err := s.readEvents(ctx, resp.Body, &request.ID)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	if resp.ContentLength == 0 && resp.StatusCode == http.StatusAccepted {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	// A single JSON-RPC reply large enough to clear the streaming
+	// threshold is handed back with its result unbuffered; the caller that
+	// asked for streaming owns resp.Body from here and must close it via
+	// ResultReader.
+	if streamingThreshold > 0 && resp.ContentLength >= streamingThreshold {
+		return s.decodeStreamingResult(resp)
+	}
+	defer resp.Body.Close()
+
+	var response JSONRPCResponse
+	if err := s.codec.Decode(resp.Body, &response); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &response, nil
+}
+
+// decodeStreamingResult decodes resp's JSON-RPC envelope via
+// decodeStreamingEnvelope, the same code InProcessTransport uses. The
+// caller has already established from resp.ContentLength that "result" is
+// worth streaming, so it passes a threshold of 0 to force that decision
+// rather than re-deciding it from a peek.
+func (s *StreamableHTTP) decodeStreamingResult(resp *http.Response) (*JSONRPCResponse, error) {
+	return decodeStreamingEnvelope(resp.Body, 0)
+}
+
+// SendBatch POSTs requests together as a single JSON-RPC 2.0 §6 batch and
+// waits for all of their responses, returned in requests' order.
+func (s *StreamableHTTP) SendBatch(ctx context.Context, requests []JSONRPCRequest) ([]*JSONRPCResponse, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	body, err := marshal(s.codec, requests)
+	if err != nil {
+		return nil, fmt.Errorf("marshal batch: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	if sid := s.getSessionID(); sid != "" {
+		httpReq.Header.Set("Mcp-Session-Id", sid)
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		s.setSessionID(sid)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("batch request failed with status %d: %s", resp.StatusCode, string(data))
+	}
+
+	// An all-notification batch (nothing here, since requests always
+	// expect a response, but a mixed batch sent by other SDKs may still do
+	// this) draws an empty 202 from the server.
+	if resp.ContentLength == 0 && resp.StatusCode == http.StatusAccepted {
+		return nil, nil
+	}
+
+	ids := make([]string, len(requests))
+	for i, req := range requests {
+		ids[i] = req.ID.String()
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		responses, err := s.readBatchEvents(ctx, resp.Body, ids)
+		if err != nil {
+			return nil, err
+		}
+		return responses, nil
+	}
+
+	var responses []*JSONRPCResponse
+	if err := s.codec.Decode(resp.Body, &responses); err != nil {
+		return nil, fmt.Errorf("decode batch response: %w", err)
+	}
+	return orderResponsesByRequest(requests, responses), nil
+}
+
+// SendNotification sends a notification to the server; no response is
+// expected.
+func (s *StreamableHTTP) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	body, err := marshal(s.codec, notification)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if sid := s.getSessionID(); sid != "" {
+		httpReq.Header.Set("Mcp-Session-Id", sid)
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// SetNotificationHandler sets the handler invoked for notifications pushed
+// by the server.
+func (s *StreamableHTTP) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	s.notifMu.Lock()
+	defer s.notifMu.Unlock()
+	s.notificationHandler = handler
+}
+
+// RegisterRequestHandler registers the handler invoked when the server
+// issues a request back to this client.
+func (s *StreamableHTTP) RegisterRequestHandler(method string, handler RequestHandlerFunc) {
+	s.reqHandlersMu.Lock()
+	defer s.reqHandlersMu.Unlock()
+	s.requestHandlers[method] = handler
+}
+
+// Close shuts down the transport, aborting any background listen stream
+// started by Start. This cancels that stream's request context so a read
+// blocked inside it (the normal steady state for a long-lived SSE
+// connection) is unblocked immediately rather than left to hang until the
+// server closes the connection on its own.
+func (s *StreamableHTTP) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+		s.listenMu.Lock()
+		if s.listenCancel != nil {
+			s.listenCancel()
+		}
+		s.listenMu.Unlock()
+	})
+	s.wg.Wait()
+	return nil
+}
+
+func (s *StreamableHTTP) getSessionID() string {
+	s.sessionMu.RLock()
+	defer s.sessionMu.RUnlock()
+	return s.sessionID
+}
+
+func (s *StreamableHTTP) setSessionID(id string) {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	s.sessionID = id
+}
+
+func (s *StreamableHTTP) getLastEventID() string {
+	s.lastEventMu.RLock()
+	defer s.lastEventMu.RUnlock()
+	return s.lastEventID
+}
+
+func (s *StreamableHTTP) setLastEventID(id string) {
+	s.lastEventMu.Lock()
+	defer s.lastEventMu.Unlock()
+	s.lastEventID = id
+}
+
+// readEvents reads SSE events from body until it finds the JSON-RPC
+// response matching wantID (in which case it returns it), or until the
+// stream ends (in which case wantID is nil for a background listener, and
+// readEvents returns nil, nil once the peer closes the connection).
+// Notifications and server-initiated requests encountered along the way are
+// dispatched as they arrive.
+func (s *StreamableHTTP) readEvents(ctx context.Context, body io.Reader, wantID *mcp.RequestId) (*JSONRPCResponse, error) {
+	var result *JSONRPCResponse
+	err := s.scanSSE(ctx, body, func(resp *JSONRPCResponse) bool {
+		if wantID != nil && resp.ID.String() == wantID.String() {
+			result = resp
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if wantID == nil {
+		return nil, nil
+	}
+	if result == nil {
+		return nil, fmt.Errorf("event stream closed before a response was received")
+	}
+	return result, nil
+}
+
+// readBatchEvents reads SSE events from body, collecting the JSON-RPC
+// responses whose ID matches one of ids, until all of them have been seen
+// or the stream ends. The returned slice has the same length and order as
+// ids; a response that never arrived is left nil.
+func (s *StreamableHTTP) readBatchEvents(ctx context.Context, body io.Reader, ids []string) ([]*JSONRPCResponse, error) {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	collected := make(map[string]*JSONRPCResponse, len(ids))
+	err := s.scanSSE(ctx, body, func(resp *JSONRPCResponse) bool {
+		if key := resp.ID.String(); want[key] {
+			collected[key] = resp
+		}
+		return len(collected) == len(want)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]*JSONRPCResponse, len(ids))
+	for i, id := range ids {
+		ordered[i] = collected[id]
+	}
+	return ordered, nil
+}
+
+// scanSSE reads SSE events from body, dispatching notifications and
+// server-initiated requests as they're seen and invoking onResponse for
+// every event that is a JSON-RPC response; onResponse returns true to stop
+// scanning early, e.g. once the caller has everything it's waiting for.
+func (s *StreamableHTTP) scanSSE(ctx context.Context, body io.Reader, onResponse func(*JSONRPCResponse) bool) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	// Per the SSE spec, an event with no explicit "event:" field defaults
+	// to type "message"; that is the only type the StreamableHTTP protocol
+	// uses today, so any other type is ignored.
+	eventType := "message"
+	var data strings.Builder
+	var id string
+
+	flush := func() (*JSONRPCResponse, error) {
+		defer func() {
+			eventType = "message"
+			data.Reset()
+			id = ""
+		}()
+		if id != "" {
+			s.setLastEventID(id)
+		}
+		if data.Len() == 0 || eventType != "message" {
+			return nil, nil
+		}
+		return s.handleSSEData(ctx, data.String())
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			resp, err := flush()
+			if err != nil {
+				return err
+			}
+			if resp != nil && onResponse(resp) {
+				return nil
+			}
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		default:
+			// Comment lines (starting with ":") and any other SSE fields
+			// are ignored here.
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read event stream: %w", err)
+	}
+	return nil
+}
+
+// handleSSEData interprets the payload of a single SSE event as a JSON-RPC
+// message: a notification and a server-initiated request are dispatched
+// immediately and handleSSEData returns nil; a response is returned to the
+// caller to match against whatever it's waiting for.
+func (s *StreamableHTTP) handleSSEData(ctx context.Context, data string) (*JSONRPCResponse, error) {
+	var peek struct {
+		Method string         `json:"method"`
+		ID     *mcp.RequestId `json:"id"`
+	}
+	if err := unmarshal(s.codec, []byte(data), &peek); err != nil {
+		return nil, fmt.Errorf("decode event data: %w", err)
+	}
+
+	switch {
+	case peek.Method != "" && peek.ID == nil:
+		var notification mcp.JSONRPCNotification
+		if err := unmarshal(s.codec, []byte(data), &notification); err != nil {
+			return nil, fmt.Errorf("decode notification: %w", err)
+		}
+		s.notifMu.RLock()
+		handler := s.notificationHandler
+		s.notifMu.RUnlock()
+		if handler != nil {
+			handler(notification)
+		}
+		return nil, nil
+
+	case peek.Method != "" && peek.ID != nil:
+		var request JSONRPCRequest
+		if err := unmarshal(s.codec, []byte(data), &request); err != nil {
+			return nil, fmt.Errorf("decode server request: %w", err)
+		}
+		s.handleIncomingRequest(request)
+		return nil, nil
+
+	default:
+		var response JSONRPCResponse
+		if err := unmarshal(s.codec, []byte(data), &response); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		return &response, nil
+	}
+}
+
+// handleIncomingRequest runs the handler registered for request.Method in
+// its own goroutine with a context tied to the transport's lifetime, then
+// POSTs the result back to the server on the same session.
+func (s *StreamableHTTP) handleIncomingRequest(request JSONRPCRequest) {
+	s.reqHandlersMu.RLock()
+	handler, ok := s.requestHandlers[request.Method]
+	s.reqHandlersMu.RUnlock()
+
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-s.closeCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		response := JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID}
+		if !ok {
+			response.Error = &mcp.JSONRPCError{Code: -32601, Message: fmt.Sprintf("method not found: %s", request.Method)}
+		} else if result, err := handler(ctx, request); err != nil {
+			response.Error = &mcp.JSONRPCError{Code: -32000, Message: err.Error()}
+		} else if resultBytes, err := marshal(s.codec, result); err != nil {
+			response.Error = &mcp.JSONRPCError{Code: -32000, Message: err.Error()}
+		} else {
+			response.Result = resultBytes
+		}
+
+		s.postResponse(ctx, response)
+	}()
+}
+
+// postResponse sends a client-originated JSON-RPC response back to the
+// server over the same session, in answer to a server-initiated request.
+func (s *StreamableHTTP) postResponse(ctx context.Context, response JSONRPCResponse) {
+	body, err := marshal(s.codec, response)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if sid := s.getSessionID(); sid != "" {
+		req.Header.Set("Mcp-Session-Id", sid)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+}
@@ -0,0 +1,272 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// startMockLargeResultServer starts a server that replies to "debug/echo"
+// with a JSON-RPC response whose "result" field is a resultSize-byte
+// string, with Content-Length set explicitly so the client can make a
+// streaming decision off a trustworthy value.
+func startMockLargeResultServer(resultSize int) (string, func()) {
+	value := strings.Repeat("x", resultSize)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body, err := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result":  value,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	})
+
+	testServer := httptest.NewServer(handler)
+	return testServer.URL, testServer.Close
+}
+
+func TestStreamableHTTPStreamsLargeResult(t *testing.T) {
+	const resultSize = 64 * 1024
+	url, closeF := startMockLargeResultServer(resultSize)
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url, WithStreamingThreshold(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	response, err := trans.SendRequest(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ResultReader == nil {
+		t.Fatal("expected ResultReader to be set for a result above the streaming threshold")
+	}
+	defer response.ResultReader.Close()
+	if response.Result != nil {
+		t.Errorf("expected Result to be empty when ResultReader is set, got %d bytes", len(response.Result))
+	}
+
+	data, err := io.ReadAll(response.ResultReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("ResultReader did not contain a bare JSON string value: %v (%q)", err, data)
+	}
+	if len(got) != resultSize {
+		t.Errorf("expected a %d-byte result, got %d", resultSize, len(got))
+	}
+}
+
+func TestStreamableHTTPBelowThresholdStillBuffers(t *testing.T) {
+	url, closeF := startMockLargeResultServer(16)
+	defer closeF()
+
+	trans, err := NewStreamableHTTP(url, WithStreamingThreshold(1024*1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	response, err := trans.SendRequest(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ResultReader != nil {
+		t.Error("expected ResultReader to stay nil for a result below the streaming threshold")
+	}
+	if len(response.Result) == 0 {
+		t.Error("expected Result to be populated")
+	}
+}
+
+func TestStreamableHTTPSendRequestStreamingOverridesDefault(t *testing.T) {
+	const resultSize = 4096
+	url, closeF := startMockLargeResultServer(resultSize)
+	defer closeF()
+
+	// No WithStreamingThreshold configured: SendRequest would buffer.
+	trans, err := NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	response, err := trans.SendRequestStreaming(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo",
+	}, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ResultReader == nil {
+		t.Fatal("expected the per-call threshold to trigger streaming")
+	}
+	response.ResultReader.Close()
+}
+
+func TestStreamableHTTPSendRequestStreamingZeroThresholdDisablesStreaming(t *testing.T) {
+	const resultSize = 64 * 1024
+	url, closeF := startMockLargeResultServer(resultSize)
+	defer closeF()
+
+	// Configured to stream by default...
+	trans, err := NewStreamableHTTP(url, WithStreamingThreshold(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	// ...but a per-call threshold of 0 must disable streaming for this
+	// call, the same as InProcessTransport.SendRequestStreaming does.
+	response, err := trans.SendRequestStreaming(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo",
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ResultReader != nil {
+		t.Error("expected threshold<=0 to disable streaming for this call")
+	}
+	if len(response.Result) == 0 {
+		t.Error("expected Result to be populated")
+	}
+}
+
+// jsonValueReaderTestCases exercises newJSONValueReader directly against
+// every JSON value shape, each followed by trailing bytes that must be left
+// unread.
+func TestJSONValueReader(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    string
+		trailer string
+	}{
+		{"object", `{"a":1,"b":[1,2]}trailer`, `{"a":1,"b":[1,2]}`, "trailer"},
+		{"array", `[1,2,3]trailer`, `[1,2,3]`, "trailer"},
+		{"string", `"hello \"world\""trailer`, `"hello \"world\""`, "trailer"},
+		{"number", `42,"next"`, `42`, `,"next"`},
+		{"bool", `true}`, `true`, `}`},
+		{"null", `null]`, `null`, `]`},
+		{"leading whitespace", "  \t\n{\"a\":1}rest", `{"a":1}`, "rest"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			br := bufio.NewReader(strings.NewReader(c.input))
+			r := newJSONValueReader(br)
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+
+			rest, err := io.ReadAll(br)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(rest) != c.trailer {
+				t.Errorf("trailing bytes: got %q, want %q", rest, c.trailer)
+			}
+		})
+	}
+}
+
+type nopCloser struct{ io.Reader }
+
+func (nopCloser) Close() error { return nil }
+
+func TestDecodeStreamingEnvelope(t *testing.T) {
+	largeValue := strings.Repeat("y", 1024)
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":%q}`, largeValue)
+
+	response, err := decodeStreamingEnvelope(nopCloser{strings.NewReader(body)}, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ResultReader == nil {
+		t.Fatal("expected a large result to stream")
+	}
+	data, err := io.ReadAll(response.ResultReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != largeValue {
+		t.Errorf("streamed result mismatch: got %d bytes, want %d", len(got), len(largeValue))
+	}
+	if response.JSONRPC != "2.0" {
+		t.Errorf("expected envelope fields to still be decoded, got jsonrpc=%q", response.JSONRPC)
+	}
+
+	small := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":%q}`, "tiny")
+	response2, err := decodeStreamingEnvelope(nopCloser{strings.NewReader(small)}, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response2.ResultReader != nil {
+		t.Error("expected a small result not to stream")
+	}
+	var gotSmall string
+	if err := json.Unmarshal(response2.Result, &gotSmall); err != nil || gotSmall != "tiny" {
+		t.Errorf("expected buffered result %q, got %q (err %v)", "tiny", gotSmall, err)
+	}
+}
+
+func TestInProcessSendRequestStreamingFallsBackBelowThreshold(t *testing.T) {
+	srv := server.NewMCPServer("test", "1.0.0")
+	srv.RegisterHandler("debug/echo", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return "tiny", nil
+	})
+	trans := NewInProcessTransport(srv)
+
+	response, err := trans.SendRequestStreaming(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0", ID: mcp.NewRequestId(int64(1)), Method: "debug/echo",
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response == nil || response.Error != nil {
+		t.Fatalf("expected a successful response, got %+v", response)
+	}
+	if response.ResultReader != nil {
+		t.Errorf("expected streaming disabled (threshold <= 0) to leave Result buffered, got a ResultReader")
+	}
+}
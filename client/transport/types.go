@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// JSONRPCRequest is the wire representation of a JSON-RPC request sent or
+// received over a transport. It is intentionally a separate type from any
+// method-specific request struct in package mcp: transports deal in raw,
+// not-yet-dispatched messages.
+type JSONRPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      mcp.RequestId `json:"id"`
+	Method  string        `json:"method"`
+	Params  any           `json:"params,omitempty"`
+}
+
+// JSONRPCResponse is the wire representation of a JSON-RPC response. Result
+// is kept as raw JSON so that callers can decode it into whatever shape the
+// method they called expects.
+type JSONRPCResponse struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      mcp.RequestId     `json:"id"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *mcp.JSONRPCError `json:"error,omitempty"`
+
+	// ResultReader, when non-nil, carries Result's bytes as a stream
+	// instead: a transport sets this rather than Result when asked to
+	// stream (see StreamableHTTP's WithStreamingThreshold) and the result
+	// turned out to be large enough to do so. Callers that opted into
+	// streaming must check ResultReader first and close it once they're
+	// done reading, whether or not they read it to completion; Result is
+	// left empty whenever ResultReader is set.
+	ResultReader io.ReadCloser `json:"-"`
+}
+
+// orderResponsesByRequest matches responses back to requests by ID and
+// returns them in requests' order, since JSON-RPC 2.0 §6 does not guarantee
+// a batch response array preserves the request array's order. Requests with
+// no matching response (e.g. the server dropped one) get a nil entry.
+func orderResponsesByRequest(requests []JSONRPCRequest, responses []*JSONRPCResponse) []*JSONRPCResponse {
+	byID := make(map[string]*JSONRPCResponse, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			byID[resp.ID.String()] = resp
+		}
+	}
+
+	ordered := make([]*JSONRPCResponse, len(requests))
+	for i, req := range requests {
+		ordered[i] = byID[req.ID.String()]
+	}
+	return ordered
+}
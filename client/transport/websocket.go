@@ -0,0 +1,388 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+const (
+	wsPingInterval   = 30 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsInitialBackoff = 500 * time.Millisecond
+	wsMaxBackoff     = 30 * time.Second
+)
+
+// WebSocket implements Interface over a single persistent WebSocket
+// connection: every JSON-RPC request, response, and notification is sent as
+// its own text frame, and responses are matched back to pending requests by
+// ID the same way the StreamableHTTP transport matches responses out of its
+// SSE stream. Unlike StreamableHTTP, the connection is full-duplex, so the
+// server can push notifications or requests to the client at any time
+// without an in-flight request to ride along on.
+type WebSocket struct {
+	url    string
+	dialer *websocket.Dialer
+
+	connMu sync.RWMutex
+	conn   *websocket.Conn
+	wMu    sync.Mutex // serializes writes; gorilla connections are not safe for concurrent writers
+
+	pendingMu sync.Mutex
+	pending   map[string]chan *JSONRPCResponse
+
+	notifMu             sync.RWMutex
+	notificationHandler func(notification mcp.JSONRPCNotification)
+
+	reqHandlersMu   sync.RWMutex
+	requestHandlers map[string]RequestHandlerFunc
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewWebSocket creates a WebSocket transport targeting url (a ws:// or
+// wss:// URL). The connection is not established until Start is called.
+func NewWebSocket(url string) (*WebSocket, error) {
+	return &WebSocket{
+		url:             url,
+		dialer:          websocket.DefaultDialer,
+		pending:         make(map[string]chan *JSONRPCResponse),
+		requestHandlers: make(map[string]RequestHandlerFunc),
+		closeCh:         make(chan struct{}),
+	}, nil
+}
+
+// Start dials the server and begins the read loop. If the connection drops
+// afterward, the transport reconnects automatically with exponential
+// backoff; callers do not need to call Start again.
+func (w *WebSocket) Start(ctx context.Context) error {
+	conn, _, err := w.dialer.DialContext(ctx, w.url, nil)
+	if err != nil {
+		return fmt.Errorf("dial websocket: %w", err)
+	}
+	w.setConn(conn)
+
+	w.wg.Add(1)
+	go w.readLoop(ctx)
+
+	w.wg.Add(1)
+	go w.pingLoop(ctx)
+
+	return nil
+}
+
+// SendRequest sends request over the socket and waits for its matching
+// response, ctx cancellation, or transport Close.
+func (w *WebSocket) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	key := request.ID.String()
+
+	ch := make(chan *JSONRPCResponse, 1)
+	w.pendingMu.Lock()
+	w.pending[key] = ch
+	w.pendingMu.Unlock()
+	defer func() {
+		w.pendingMu.Lock()
+		delete(w.pending, key)
+		w.pendingMu.Unlock()
+	}()
+
+	if err := w.writeJSON(request); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-w.closeCh:
+		return nil, fmt.Errorf("websocket transport closed")
+	case response := <-ch:
+		return response, nil
+	}
+}
+
+// SendBatch writes requests to the socket as a single JSON-RPC batch frame
+// and waits for all of their responses, returned in requests' order.
+func (w *WebSocket) SendBatch(ctx context.Context, requests []JSONRPCRequest) ([]*JSONRPCResponse, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	chans := make(map[string]chan *JSONRPCResponse, len(requests))
+	w.pendingMu.Lock()
+	for _, req := range requests {
+		ch := make(chan *JSONRPCResponse, 1)
+		chans[req.ID.String()] = ch
+		w.pending[req.ID.String()] = ch
+	}
+	w.pendingMu.Unlock()
+	defer func() {
+		w.pendingMu.Lock()
+		for key := range chans {
+			delete(w.pending, key)
+		}
+		w.pendingMu.Unlock()
+	}()
+
+	if err := w.writeJSON(requests); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*JSONRPCResponse, len(requests))
+	for i, req := range requests {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.closeCh:
+			return nil, fmt.Errorf("websocket transport closed")
+		case response := <-chans[req.ID.String()]:
+			responses[i] = response
+		}
+	}
+	return responses, nil
+}
+
+// SendNotification writes notification to the socket; no response is
+// expected.
+func (w *WebSocket) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return w.writeJSON(notification)
+}
+
+// SetNotificationHandler sets the handler invoked for notifications pushed
+// by the server.
+func (w *WebSocket) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	w.notifMu.Lock()
+	defer w.notifMu.Unlock()
+	w.notificationHandler = handler
+}
+
+// RegisterRequestHandler registers the handler invoked when the server
+// issues a request back to this client over the socket.
+func (w *WebSocket) RegisterRequestHandler(method string, handler RequestHandlerFunc) {
+	w.reqHandlersMu.Lock()
+	defer w.reqHandlersMu.Unlock()
+	w.requestHandlers[method] = handler
+}
+
+// Close drains any in-flight SendRequest calls with an error and closes the
+// underlying connection, sending a proper WebSocket close frame first.
+func (w *WebSocket) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+
+		if conn := w.getConn(); conn != nil {
+			w.wMu.Lock()
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			w.wMu.Unlock()
+			_ = conn.Close()
+		}
+	})
+	w.wg.Wait()
+	return nil
+}
+
+func (w *WebSocket) getConn() *websocket.Conn {
+	w.connMu.RLock()
+	defer w.connMu.RUnlock()
+	return w.conn
+}
+
+func (w *WebSocket) setConn(conn *websocket.Conn) {
+	w.connMu.Lock()
+	defer w.connMu.Unlock()
+	w.conn = conn
+}
+
+func (w *WebSocket) writeJSON(v any) error {
+	conn := w.getConn()
+	if conn == nil {
+		return fmt.Errorf("websocket is not connected")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+	w.wMu.Lock()
+	defer w.wMu.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// readLoop reads frames off the connection for the lifetime of the
+// transport, reconnecting with backoff whenever the connection drops
+// unexpectedly.
+func (w *WebSocket) readLoop(ctx context.Context) {
+	defer w.wg.Done()
+
+	for {
+		conn := w.getConn()
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(wsPongWait))
+			return nil
+		})
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-w.closeCh:
+				return
+			default:
+			}
+			if !w.reconnect(ctx) {
+				return
+			}
+			continue
+		}
+
+		w.handleMessage(ctx, data)
+	}
+}
+
+// reconnect redials with exponential backoff until it succeeds or the
+// transport is closed or ctx is canceled.
+func (w *WebSocket) reconnect(ctx context.Context) bool {
+	backoff := wsInitialBackoff
+	for {
+		select {
+		case <-w.closeCh:
+			return false
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+
+		conn, _, err := w.dialer.DialContext(ctx, w.url, nil)
+		if err == nil {
+			w.setConn(conn)
+			return true
+		}
+
+		backoff = time.Duration(float64(backoff) * (1.5 + rand.Float64()*0.5))
+		if backoff > wsMaxBackoff {
+			backoff = wsMaxBackoff
+		}
+	}
+}
+
+func (w *WebSocket) pingLoop(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.closeCh:
+			return
+		case <-ticker.C:
+			conn := w.getConn()
+			if conn == nil {
+				continue
+			}
+			w.wMu.Lock()
+			_ = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
+			w.wMu.Unlock()
+		}
+	}
+}
+
+// handleMessage dispatches a single decoded frame: a response to a pending
+// request, a notification, or a server-initiated request.
+func (w *WebSocket) handleMessage(ctx context.Context, data []byte) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var elements []json.RawMessage
+		if err := json.Unmarshal(trimmed, &elements); err != nil {
+			return
+		}
+		for _, element := range elements {
+			w.handleMessage(ctx, element)
+		}
+		return
+	}
+
+	var peek struct {
+		Method string         `json:"method"`
+		ID     *mcp.RequestId `json:"id"`
+	}
+	if err := json.Unmarshal(data, &peek); err != nil {
+		return
+	}
+
+	switch {
+	case peek.Method != "" && peek.ID == nil:
+		var notification mcp.JSONRPCNotification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			return
+		}
+		w.notifMu.RLock()
+		handler := w.notificationHandler
+		w.notifMu.RUnlock()
+		if handler != nil {
+			handler(notification)
+		}
+
+	case peek.Method != "" && peek.ID != nil:
+		var request JSONRPCRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			return
+		}
+		w.handleIncomingRequest(ctx, request)
+
+	default:
+		var response JSONRPCResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			return
+		}
+		w.pendingMu.Lock()
+		ch, ok := w.pending[response.ID.String()]
+		w.pendingMu.Unlock()
+		if ok {
+			ch <- &response
+		}
+	}
+}
+
+// handleIncomingRequest runs the handler registered for request.Method in
+// its own goroutine with a context derived from ctx, writing the result
+// back over the socket once it completes.
+func (w *WebSocket) handleIncomingRequest(ctx context.Context, request JSONRPCRequest) {
+	w.reqHandlersMu.RLock()
+	handler, ok := w.requestHandlers[request.Method]
+	w.reqHandlersMu.RUnlock()
+
+	go func() {
+		handlerCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		response := JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID}
+		switch {
+		case !ok:
+			response.Error = &mcp.JSONRPCError{Code: -32601, Message: fmt.Sprintf("method not found: %s", request.Method)}
+		default:
+			result, err := handler(handlerCtx, request)
+			if err != nil {
+				response.Error = &mcp.JSONRPCError{Code: -32000, Message: err.Error()}
+			} else if resultBytes, err := json.Marshal(result); err != nil {
+				response.Error = &mcp.JSONRPCError{Code: -32000, Message: err.Error()}
+			} else {
+				response.Result = resultBytes
+			}
+		}
+
+		_ = w.writeJSON(response)
+	}()
+}
@@ -0,0 +1,171 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// startMockWebSocketServer starts a test HTTP server that upgrades to a
+// WebSocket and echoes "debug/echo" requests, pushes a notification for
+// "debug/echo_notification", and otherwise ignores unknown methods.
+func startMockWebSocketServer(t *testing.T) (string, func()) {
+	upgrader := websocket.Upgrader{}
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var request map[string]any
+			if err := json.Unmarshal(data, &request); err != nil {
+				continue
+			}
+
+			switch request["method"] {
+			case "debug/echo":
+				response, _ := json.Marshal(map[string]any{
+					"jsonrpc": "2.0",
+					"id":      request["id"],
+					"result":  request,
+				})
+				conn.WriteMessage(websocket.TextMessage, response)
+
+			case "debug/echo_notification":
+				notification, _ := json.Marshal(map[string]any{
+					"jsonrpc": "2.0",
+					"method":  "debug/test",
+					"params":  request,
+				})
+				conn.WriteMessage(websocket.TextMessage, notification)
+
+				response, _ := json.Marshal(map[string]any{
+					"jsonrpc": "2.0",
+					"id":      request["id"],
+					"result":  request,
+				})
+				conn.WriteMessage(websocket.TextMessage, response)
+			}
+		}
+	}))
+
+	wsURL := "ws" + strings.TrimPrefix(testServer.URL, "http")
+	return wsURL, testServer.Close
+}
+
+func TestWebSocket(t *testing.T) {
+	url, closeF := startMockWebSocketServer(t)
+	defer closeF()
+
+	trans, err := NewWebSocket(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer trans.Close()
+
+	t.Run("SendRequest", func(t *testing.T) {
+		request := JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      mcp.NewRequestId(int64(1)),
+			Method:  "debug/echo",
+			Params:  map[string]any{"string": "hello world"},
+		}
+
+		response, err := trans.SendRequest(ctx, request)
+		if err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+
+		var result struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("failed to unmarshal result: %v", err)
+		}
+		if result.Method != "debug/echo" {
+			t.Errorf("expected method 'debug/echo', got %q", result.Method)
+		}
+	})
+
+	t.Run("SendRequestWithTimeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		request := JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      mcp.NewRequestId(int64(2)),
+			Method:  "debug/echo",
+		}
+
+		_, err := trans.SendRequest(ctx, request)
+		if err == nil {
+			t.Error("expected context canceled error, got nil")
+		}
+	})
+
+	t.Run("Notification", func(t *testing.T) {
+		notificationChan := make(chan mcp.JSONRPCNotification, 1)
+		trans.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+			notificationChan <- notification
+		})
+
+		request := JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      mcp.NewRequestId(int64(3)),
+			Method:  "debug/echo_notification",
+		}
+
+		if _, err := trans.SendRequest(ctx, request); err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+
+		select {
+		case notification := <-notificationChan:
+			if notification.Method != "debug/test" {
+				t.Errorf("expected method 'debug/test', got %q", notification.Method)
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("expected notification, got none")
+		}
+	})
+}
+
+func TestWebSocketErrors(t *testing.T) {
+	t.Run("NonExistentURL", func(t *testing.T) {
+		trans, err := NewWebSocket("ws://localhost:1")
+		if err != nil {
+			t.Fatalf("NewWebSocket failed: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if err := trans.Start(ctx); err == nil {
+			t.Error("expected error connecting to non-existent URL, got nil")
+		}
+	})
+}
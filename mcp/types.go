@@ -0,0 +1,131 @@
+// Package mcp defines the core protocol types shared by MCP clients and
+// servers, independent of any particular transport.
+package mcp
+
+import "encoding/json"
+
+// JSONRPC_VERSION is the version of JSON-RPC used by MCP.
+const JSONRPC_VERSION = "2.0"
+
+// JSONRPCMessage is any of the concrete JSON-RPC message types (request,
+// response, or notification). It exists so transport-agnostic code can pass
+// "a message" around without committing to which kind it is.
+type JSONRPCMessage any
+
+// RequestId is a uniquely identifying ID for a request in JSON-RPC.
+//
+// It can be any JSON-serializable value, but per the spec SHOULD be a string
+// or integer. We keep it as an opaque wrapper so that round-tripping through
+// JSON preserves the caller's original type (e.g. an int64 ID sent by a
+// client is not silently turned into a float64).
+type RequestId struct {
+	value any
+}
+
+// NewRequestId creates a new RequestId wrapping the given value.
+func NewRequestId(value any) RequestId {
+	return RequestId{value: value}
+}
+
+// Value returns the underlying value of the request ID.
+func (r RequestId) Value() any {
+	return r.value
+}
+
+// String returns a string representation of the request ID, for logging.
+func (r RequestId) String() string {
+	switch v := r.value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r RequestId) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Whole numbers are normalized to
+// int64 so that callers can type-assert on a stable type regardless of
+// whether the ID arrived as a JSON number or was constructed in Go.
+func (r *RequestId) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if f, ok := value.(float64); ok && f == float64(int64(f)) {
+		value = int64(f)
+	}
+
+	r.value = value
+	return nil
+}
+
+// Notification is the common shape shared by all MCP notifications.
+type Notification struct {
+	Method string             `json:"method"`
+	Params NotificationParams `json:"params,omitempty"`
+}
+
+// NotificationParams carries a notification's well-known _meta field plus
+// any method-specific fields, which are flattened into AdditionalFields
+// during (un)marshaling so callers don't need a different struct per
+// notification method.
+type NotificationParams struct {
+	Meta             map[string]any `json:"_meta,omitempty"`
+	AdditionalFields map[string]any `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, flattening AdditionalFields
+// alongside the well-known fields.
+func (n NotificationParams) MarshalJSON() ([]byte, error) {
+	merged := make(map[string]any, len(n.AdditionalFields)+1)
+	for k, v := range n.AdditionalFields {
+		merged[k] = v
+	}
+	if n.Meta != nil {
+		merged["_meta"] = n.Meta
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, pulling the well-known _meta
+// field out of the payload and leaving everything else in AdditionalFields.
+func (n *NotificationParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if meta, ok := raw["_meta"]; ok {
+		if metaMap, ok := meta.(map[string]any); ok {
+			n.Meta = metaMap
+		}
+		delete(raw, "_meta")
+	}
+
+	n.AdditionalFields = raw
+	return nil
+}
+
+// JSONRPCNotification is a notification which does not expect a response.
+type JSONRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Notification
+}
+
+// JSONRPCError represents a JSON-RPC error object as defined by the spec.
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
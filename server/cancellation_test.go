@@ -0,0 +1,88 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/client/transport"
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// TestMCPServerCancelsInFlightHandler proves a real server.MCPServer aborts
+// a running handler when it receives notifications/cancelled for that
+// request's ID, rather than silently dropping the notification as an
+// unrecognized method.
+func TestMCPServerCancelsInFlightHandler(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.0.0")
+
+	started := make(chan struct{})
+	handlerErr := make(chan error, 1)
+	srv.RegisterHandler("debug/block", func(ctx context.Context, params json.RawMessage) (any, error) {
+		close(started)
+		<-ctx.Done()
+		handlerErr <- ctx.Err()
+		return nil, ctx.Err()
+	})
+
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(srv))
+	defer httpServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "initialize",
+	}); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	blockedID := mcp.NewRequestId(int64(2))
+
+	// debug/block never returns on its own, so the response arrives (if at
+	// all) only once the handler observes cancellation; send it without
+	// waiting for a reply.
+	go trans.SendRequest(context.Background(), transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      blockedID,
+		Method:  "debug/block",
+	})
+
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for debug/block to start")
+	}
+
+	if err := trans.SendNotification(ctx, mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: "notifications/cancelled",
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{"requestId": blockedID.Value()},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("send notifications/cancelled: %v", err)
+	}
+
+	select {
+	case err := <-handlerErr:
+		if err != context.Canceled {
+			t.Errorf("expected the handler's context to be canceled, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for debug/block's handler to observe cancellation")
+	}
+}
@@ -0,0 +1,295 @@
+// Package server implements the server half of MCP: dispatching incoming
+// JSON-RPC requests from connected clients and, where the transport
+// supports it, issuing requests back to them.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// RequestHandlerFunc handles one method's params and returns the value to
+// place in the JSON-RPC response's result field, or an error to report as a
+// JSON-RPC error instead.
+type RequestHandlerFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// MCPServer is the core, transport-agnostic MCP server. Transports (stdio,
+// StreamableHTTP, in-process, ...) sit on top of it: they turn bytes on the
+// wire into calls to HandleMessage and register the sessions they own so
+// the server can reach back out to them.
+type MCPServer struct {
+	name    string
+	version string
+
+	mu       sync.RWMutex
+	sessions map[string]ClientSession
+
+	handlersMu sync.RWMutex
+	handlers   map[string]RequestHandlerFunc
+
+	// cancelMu guards inFlight, which tracks the cancel func for every
+	// request currently being dispatched, keyed by its session and request
+	// ID, so a notifications/cancelled notification can abort it.
+	cancelMu sync.Mutex
+	inFlight map[string]context.CancelFunc
+}
+
+// NewMCPServer creates a new MCPServer with the given name and version,
+// used to identify the server during the MCP initialize handshake. The
+// initialize and ping methods are handled out of the box; callers register
+// everything else (tools/call, resources/read, ...) with RegisterHandler.
+func NewMCPServer(name, version string) *MCPServer {
+	s := &MCPServer{
+		name:     name,
+		version:  version,
+		sessions: make(map[string]ClientSession),
+		handlers: make(map[string]RequestHandlerFunc),
+		inFlight: make(map[string]context.CancelFunc),
+	}
+	s.RegisterHandler("initialize", s.handleInitialize)
+	s.RegisterHandler("ping", s.handlePing)
+	s.RegisterHandler("notifications/cancelled", s.handleCancelled)
+	return s
+}
+
+// RegisterHandler registers the function that answers requests for method,
+// replacing any handler previously registered for it. It is safe to call
+// concurrently with HandleMessage.
+func (s *MCPServer) RegisterHandler(method string, handler RequestHandlerFunc) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	s.handlers[method] = handler
+}
+
+// handleInitialize is the built-in handler for the MCP initialize
+// handshake. It echoes back the client's protocol version alongside the
+// server's identity; callers that need to negotiate capabilities can
+// replace it with RegisterHandler("initialize", ...).
+func (s *MCPServer) handleInitialize(ctx context.Context, params json.RawMessage) (any, error) {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, fmt.Errorf("invalid initialize params: %w", err)
+		}
+	}
+
+	return map[string]any{
+		"protocolVersion": req.ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"serverInfo": map[string]any{
+			"name":    s.name,
+			"version": s.version,
+		},
+	}, nil
+}
+
+// handlePing is the built-in handler for the MCP liveness check: it takes
+// no params and always succeeds with an empty result.
+func (s *MCPServer) handlePing(ctx context.Context, params json.RawMessage) (any, error) {
+	return map[string]any{}, nil
+}
+
+// handleCancelled is the built-in handler for notifications/cancelled: it
+// looks up the in-flight request named by params.requestId on this session
+// and cancels its context, aborting whatever handler is still running for
+// it. A requestId with no matching in-flight request (already finished, or
+// never existed) is ignored, as the spec requires.
+func (s *MCPServer) handleCancelled(ctx context.Context, params json.RawMessage) (any, error) {
+	var req struct {
+		RequestId mcp.RequestId `json:"requestId"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid notifications/cancelled params: %w", err)
+	}
+
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, nil
+	}
+
+	key := inFlightKey(session.SessionID(), req.RequestId)
+	s.cancelMu.Lock()
+	cancel, ok := s.inFlight[key]
+	s.cancelMu.Unlock()
+	if ok {
+		cancel()
+	}
+	return nil, nil
+}
+
+// inFlightKey identifies a request being dispatched for cancellation
+// purposes. Request IDs are only unique within a session, so both are
+// needed to address one in-flight call.
+func inFlightKey(sessionID string, id mcp.RequestId) string {
+	return sessionID + ":" + id.String()
+}
+
+// RegisterSession makes session known to the server so it can be
+// addressed later, e.g. by SendRequestToClient. Transports call this once a
+// session has been established.
+func (s *MCPServer) RegisterSession(session ClientSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.SessionID()] = session
+}
+
+// UnregisterSession removes a session previously added with RegisterSession.
+func (s *MCPServer) UnregisterSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+}
+
+// SendRequestToClient issues method/params as a JSON-RPC request to the
+// client of the session carried in ctx (see WithContext) and waits for its
+// response. It returns an error if ctx carries no session, or if the
+// session's transport does not support server-initiated requests.
+func (s *MCPServer) SendRequestToClient(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, fmt.Errorf("no client session in context")
+	}
+
+	requestCapable, ok := session.(RequestCapableSession)
+	if !ok {
+		return nil, fmt.Errorf("session %s's transport does not support server-initiated requests", session.SessionID())
+	}
+
+	return requestCapable.SendRequestToClient(ctx, method, params)
+}
+
+// HandleMessage dispatches a single raw JSON-RPC message from session and
+// returns the JSON-RPC response to send back, or nil for notifications
+// (which have no response). message may also be a JSON-RPC 2.0 §6 batch
+// (a top-level JSON array); in that case HandleMessage returns a
+// []mcp.JSONRPCMessage holding one entry per non-notification element, or
+// nil if the batch contained only notifications.
+func (s *MCPServer) HandleMessage(ctx context.Context, session ClientSession, message json.RawMessage) mcp.JSONRPCMessage {
+	ctx = WithContext(ctx, session)
+
+	if trimmed := bytes.TrimSpace(message); len(trimmed) > 0 && trimmed[0] == '[' {
+		return s.handleBatch(ctx, trimmed)
+	}
+	return s.dispatch(ctx, message)
+}
+
+// handleBatch dispatches every element of a JSON-RPC batch, potentially
+// concurrently, and collects the non-nil results (i.e. skipping
+// notifications) into a response array preserving each element's position.
+func (s *MCPServer) handleBatch(ctx context.Context, batch json.RawMessage) mcp.JSONRPCMessage {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(batch, &elements); err != nil {
+		return &mcp.JSONRPCError{Code: -32700, Message: fmt.Sprintf("parse error: %v", err)}
+	}
+
+	results := make([]mcp.JSONRPCMessage, len(elements))
+	var wg sync.WaitGroup
+	for i, element := range elements {
+		wg.Add(1)
+		go func(i int, element json.RawMessage) {
+			defer wg.Done()
+			results[i] = s.dispatch(ctx, element)
+		}(i, element)
+	}
+	wg.Wait()
+
+	responses := make([]mcp.JSONRPCMessage, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			responses = append(responses, result)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// wireRequest is the shape this package reads an incoming JSON-RPC request
+// or notification as. ID is nil for notifications, which get no response.
+type wireRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *mcp.RequestId  `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// wireResponse is the shape this package writes a JSON-RPC response as.
+// Transports marshal it as-is, so its field names and omitempty behavior
+// must match the JSON-RPC 2.0 wire format exactly.
+type wireResponse struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      mcp.RequestId     `json:"id"`
+	Result  any               `json:"result,omitempty"`
+	Error   *mcp.JSONRPCError `json:"error,omitempty"`
+}
+
+// dispatch routes a single decoded request or notification to the handler
+// registered for its method (see RegisterHandler) and returns the response
+// to send back. Notifications (no "id") always return nil; a request whose
+// method has no registered handler gets a "method not found" error response.
+func (s *MCPServer) dispatch(ctx context.Context, message json.RawMessage) mcp.JSONRPCMessage {
+	var req wireRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return &mcp.JSONRPCError{Code: -32700, Message: fmt.Sprintf("parse error: %v", err)}
+	}
+
+	s.handlersMu.RLock()
+	handler, ok := s.handlers[req.Method]
+	s.handlersMu.RUnlock()
+
+	if !ok {
+		if req.ID == nil {
+			return nil
+		}
+		return &wireResponse{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      *req.ID,
+			Error:   &mcp.JSONRPCError{Code: -32601, Message: fmt.Sprintf("method not found: %s", req.Method)},
+		}
+	}
+
+	if req.ID != nil {
+		if session := ClientSessionFromContext(ctx); session != nil {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			key := inFlightKey(session.SessionID(), *req.ID)
+			s.cancelMu.Lock()
+			s.inFlight[key] = cancel
+			s.cancelMu.Unlock()
+			defer func() {
+				s.cancelMu.Lock()
+				delete(s.inFlight, key)
+				s.cancelMu.Unlock()
+				cancel()
+			}()
+		}
+	}
+
+	result, err := handler(ctx, req.Params)
+	if req.ID == nil {
+		// Notifications get no response, regardless of the handler's
+		// outcome.
+		return nil
+	}
+
+	if err != nil {
+		return &wireResponse{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      *req.ID,
+			Error:   &mcp.JSONRPCError{Code: -32603, Message: err.Error()},
+		}
+	}
+	return &wireResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      *req.ID,
+		Result:  result,
+	}
+}
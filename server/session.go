@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+// ClientSession represents an active connection between a client and the
+// server, independent of which transport carries it.
+type ClientSession interface {
+	// SessionID is a unique identifier for this session, stable for its
+	// lifetime.
+	SessionID() string
+
+	// NotificationChannel returns a channel to which server-to-client
+	// notifications for this session should be sent.
+	NotificationChannel() chan<- mcp.JSONRPCNotification
+
+	// Initialized reports whether the session has completed the MCP
+	// initialize handshake.
+	Initialized() bool
+}
+
+// RequestCapableSession is implemented by sessions whose transport supports
+// the server issuing requests back to the client (sampling/createMessage,
+// roots/list, elicitation/create, ...) and awaiting a response. Not every
+// transport can do this (e.g. a transport with no way to push data to the
+// client), so it is a separate, optional interface from ClientSession.
+type RequestCapableSession interface {
+	ClientSession
+
+	// SendRequestToClient sends method/params to the client owning this
+	// session and blocks until a response arrives or ctx is done.
+	SendRequestToClient(ctx context.Context, method string, params any) (json.RawMessage, error)
+}
+
+type clientSessionKey struct{}
+
+// ClientSessionFromContext extracts the ClientSession previously stored by
+// WithContext, if any.
+func ClientSessionFromContext(ctx context.Context) ClientSession {
+	session, _ := ctx.Value(clientSessionKey{}).(ClientSession)
+	return session
+}
+
+// WithContext returns a copy of ctx carrying session, so that handlers
+// invoked while processing a request from this session can later call back
+// into it (e.g. via MCPServer.SendRequestToClient).
+func WithContext(ctx context.Context, session ClientSession) context.Context {
+	return context.WithValue(ctx, clientSessionKey{}, session)
+}
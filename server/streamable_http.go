@@ -0,0 +1,395 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+const defaultReplayBufferSize = 100
+
+// StreamableHTTPServer serves an MCPServer over the MCP "Streamable HTTP"
+// transport: client-to-server messages arrive as POSTs, while a session's
+// GET request is kept open as an SSE stream the server pushes notifications
+// down. To survive a dropped GET connection, every pushed notification is
+// also kept in a small per-session ring buffer so a client reconnecting with
+// a Last-Event-ID header can replay whatever it missed instead of losing it.
+type StreamableHTTPServer struct {
+	server           *MCPServer
+	replayBufferSize int
+
+	mu       sync.Mutex
+	sessions map[string]*streamableHTTPSession
+}
+
+// StreamableHTTPServerOption configures a StreamableHTTPServer.
+type StreamableHTTPServerOption func(*StreamableHTTPServer)
+
+// WithReplayBufferSize sets how many recent events each session keeps
+// around for replay after a reconnect. The default is 100.
+func WithReplayBufferSize(n int) StreamableHTTPServerOption {
+	return func(s *StreamableHTTPServer) {
+		s.replayBufferSize = n
+	}
+}
+
+// NewStreamableHTTPServer wraps srv to be served over StreamableHTTP.
+func NewStreamableHTTPServer(srv *MCPServer, opts ...StreamableHTTPServerOption) *StreamableHTTPServer {
+	s := &StreamableHTTPServer{
+		server:           srv,
+		replayBufferSize: defaultReplayBufferSize,
+		sessions:         make(map[string]*streamableHTTPSession),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ServeHTTP handles both halves of the protocol: POST delivers a
+// request/notification/batch from the client, and GET opens (or resumes,
+// via a Last-Event-ID header) this session's server-push stream.
+func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.serveListen(w, r)
+	case http.MethodPost:
+		s.servePost(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *StreamableHTTPServer) sessionFor(id string) *streamableHTTPSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		session = newStreamableHTTPSession(id, s.replayBufferSize)
+		s.sessions[id] = session
+		s.server.RegisterSession(session)
+	}
+	return session
+}
+
+func (s *StreamableHTTPServer) servePost(w http.ResponseWriter, r *http.Request) {
+	body, err := readAll(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sessionID := r.Header.Get("Mcp-Session-Id")
+
+	var peek struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	}
+	isInitialize := json.Unmarshal(body, &peek) == nil && peek.Method == "initialize"
+	if sessionID == "" && isInitialize {
+		sessionID = newSessionID()
+	}
+
+	session := s.sessionFor(sessionID)
+	if isInitialize {
+		session.initialized = true
+		w.Header().Set("Mcp-Session-Id", sessionID)
+	}
+
+	if peek.Method == "" && len(peek.ID) > 0 {
+		// No method but an id: not a request or notification, but the
+		// client's response to a request the server previously pushed to
+		// it via SendRequestToClient.
+		session.resolveClientResponse(body)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	ctx := WithContext(r.Context(), session)
+	response := s.server.HandleMessage(ctx, session, body)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// serveListen serves a session's long-lived push stream. A client that
+// reconnects after a drop sends Last-Event-ID with the ID of the last event
+// it saw, and receives every buffered event after it before the stream
+// continues live; a first-time connection omits the header and gets only
+// new events from this point on.
+func (s *StreamableHTTPServer) serveListen(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		http.Error(w, "missing Mcp-Session-Id", http.StatusBadRequest)
+		return
+	}
+	session := s.sessionFor(sessionID)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	lastEventID, _ := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	for _, event := range session.buffer.since(lastEventID) {
+		writeSSEEvent(w, event)
+	}
+	flusher.Flush()
+
+	sub := session.subscribe()
+	defer session.unsubscribe(sub)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub:
+			if !ok {
+				return
+			}
+			writeSSEEvent(w, event)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event sseEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", event.id, event.data)
+}
+
+func readAll(r *http.Request) ([]byte, error) {
+	defer r.Body.Close()
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(r.Body)
+	return buf.Bytes(), err
+}
+
+var sessionCounter uint64
+var sessionCounterMu sync.Mutex
+
+func newSessionID() string {
+	sessionCounterMu.Lock()
+	defer sessionCounterMu.Unlock()
+	sessionCounter++
+	return fmt.Sprintf("session-%d", sessionCounter)
+}
+
+// sseEvent is a single buffered server-push event, numbered so a
+// reconnecting client can ask to replay everything after a given ID via the
+// Last-Event-ID header.
+type sseEvent struct {
+	id   int64
+	data json.RawMessage
+}
+
+// replayBuffer is a bounded ring buffer of the most recent events pushed to
+// a session, used to answer a reconnect's Last-Event-ID.
+type replayBuffer struct {
+	mu     sync.Mutex
+	size   int
+	nextID int64
+	events []sseEvent
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	if size <= 0 {
+		size = defaultReplayBufferSize
+	}
+	return &replayBuffer{size: size}
+}
+
+func (b *replayBuffer) add(data json.RawMessage) sseEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	event := sseEvent{id: b.nextID, data: data}
+	b.events = append(b.events, event)
+	if len(b.events) > b.size {
+		b.events = b.events[len(b.events)-b.size:]
+	}
+	return event
+}
+
+func (b *replayBuffer) since(lastID int64) []sseEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []sseEvent
+	for _, event := range b.events {
+		if event.id > lastID {
+			out = append(out, event)
+		}
+	}
+	return out
+}
+
+// streamableHTTPSession implements ClientSession and RequestCapableSession
+// on top of a StreamableHTTP client: notifications and server-initiated
+// requests sent to it are both buffered (for replay after a reconnect) and
+// forwarded live to whichever GET stream is currently open, if any. A
+// server-initiated request's response arrives back as an ordinary POST (see
+// servePost) with no "method" of its own, which resolveClientResponse
+// matches to the pending call by "id".
+type streamableHTTPSession struct {
+	id     string
+	buffer *replayBuffer
+
+	initialized bool
+
+	mu   sync.Mutex
+	subs map[chan sseEvent]struct{}
+
+	notifCh chan mcp.JSONRPCNotification
+
+	nextRequestID int64
+
+	pendingMu sync.Mutex
+	pending   map[string]chan clientResponse
+}
+
+// clientResponse is what a pending SendRequestToClient call is waiting for:
+// either a result or an error decoded from the client's response.
+type clientResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+func newStreamableHTTPSession(id string, replaySize int) *streamableHTTPSession {
+	session := &streamableHTTPSession{
+		id:      id,
+		buffer:  newReplayBuffer(replaySize),
+		subs:    make(map[chan sseEvent]struct{}),
+		notifCh: make(chan mcp.JSONRPCNotification, 100),
+		pending: make(map[string]chan clientResponse),
+	}
+	go session.forwardNotifications()
+	return session
+}
+
+func (s *streamableHTTPSession) forwardNotifications() {
+	for notification := range s.notifCh {
+		data, err := json.Marshal(notification)
+		if err != nil {
+			continue
+		}
+		s.push(data)
+	}
+}
+
+// push buffers data (for replay) and forwards it to every GET stream
+// currently subscribed to this session.
+func (s *streamableHTTPSession) push(data json.RawMessage) {
+	event := s.buffer.add(data)
+
+	s.mu.Lock()
+	for sub := range s.subs {
+		select {
+		case sub <- event:
+		default:
+			// A slow subscriber misses the live push but can still catch
+			// up via the replay buffer on its next GET.
+		}
+	}
+	s.mu.Unlock()
+}
+
+// SendRequestToClient pushes method/params to the client as a JSON-RPC
+// request over this session's SSE stream and blocks until its matching
+// response arrives back over a POST, or ctx is done.
+func (s *streamableHTTPSession) SendRequestToClient(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	id := atomic.AddInt64(&s.nextRequestID, 1)
+	key := mcp.NewRequestId(id).String()
+
+	ch := make(chan clientResponse, 1)
+	s.pendingMu.Lock()
+	s.pending[key] = ch
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, key)
+		s.pendingMu.Unlock()
+	}()
+
+	data, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	s.push(data)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case resp := <-ch:
+		return resp.result, resp.err
+	}
+}
+
+// resolveClientResponse decodes body as a client's response to a pending
+// SendRequestToClient call and delivers it, if one is still waiting on its
+// "id". A response whose "error" is set resolves the call with an error
+// instead of a result.
+func (s *streamableHTTPSession) resolveClientResponse(body []byte) {
+	var resp struct {
+		ID     mcp.RequestId     `json:"id"`
+		Result json.RawMessage   `json:"result"`
+		Error  *mcp.JSONRPCError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return
+	}
+
+	s.pendingMu.Lock()
+	ch, ok := s.pending[resp.ID.String()]
+	s.pendingMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if resp.Error != nil {
+		ch <- clientResponse{err: fmt.Errorf("client returned error %d: %s", resp.Error.Code, resp.Error.Message)}
+		return
+	}
+	ch <- clientResponse{result: resp.Result}
+}
+
+func (s *streamableHTTPSession) subscribe() chan sseEvent {
+	sub := make(chan sseEvent, 16)
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *streamableHTTPSession) unsubscribe(sub chan sseEvent) {
+	s.mu.Lock()
+	delete(s.subs, sub)
+	s.mu.Unlock()
+}
+
+func (s *streamableHTTPSession) SessionID() string { return s.id }
+
+func (s *streamableHTTPSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifCh
+}
+
+func (s *streamableHTTPSession) Initialized() bool { return s.initialized }
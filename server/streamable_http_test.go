@@ -0,0 +1,203 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/client/transport"
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// TestStreamableHTTPServerInitialize drives a real client.transport.StreamableHTTP
+// against server.NewStreamableHTTPServer end to end, covering the handshake
+// that servePost's isInitialize special-case depends on MCPServer actually
+// answering.
+func TestStreamableHTTPServerInitialize(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.2.3")
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(srv))
+	defer httpServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "initialize",
+		Params:  map[string]any{"protocolVersion": "2025-03-26"},
+	})
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("expected a successful initialize response, got %+v", resp)
+	}
+
+	var result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.ProtocolVersion != "2025-03-26" {
+		t.Errorf("expected protocolVersion echoed back, got %q", result.ProtocolVersion)
+	}
+	if result.ServerInfo.Name != "test-server" || result.ServerInfo.Version != "1.2.3" {
+		t.Errorf("expected serverInfo {test-server 1.2.3}, got %+v", result.ServerInfo)
+	}
+
+	// A follow-up request on the same session should reach the registered
+	// ping handler rather than getting dropped by an un-wired dispatch.
+	pingResp, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(2)),
+		Method:  "ping",
+	})
+	if err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if pingResp == nil || pingResp.Error != nil {
+		t.Fatalf("expected a successful ping response, got %+v", pingResp)
+	}
+}
+
+// TestStreamableHTTPServerBidirectionalRequest drives a real
+// server.NewStreamableHTTPServer into sending a server-initiated request of
+// its own (over the session's SSE stream) and proves the client's POSTed
+// response makes it all the way back to the handler's SendRequestToClient
+// call, including the error case.
+func TestStreamableHTTPServerBidirectionalRequest(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.0.0")
+	srv.RegisterHandler("tools/call", func(ctx context.Context, params json.RawMessage) (any, error) {
+		result, err := srv.SendRequestToClient(ctx, "sampling/createMessage", map[string]any{"prompt": "say hi"})
+		if err != nil {
+			return nil, err
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(result, &decoded); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	})
+
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(srv))
+	defer httpServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "initialize",
+	}); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	trans.RegisterRequestHandler("sampling/createMessage", func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		return map[string]any{"text": "hi"}, nil
+	})
+
+	resp, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(2)),
+		Method:  "tools/call",
+	})
+	if err != nil {
+		t.Fatalf("tools/call: %v", err)
+	}
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("expected a successful tools/call response, got %+v", resp)
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.Text != "hi" {
+		t.Errorf("expected text %q, got %q", "hi", result.Text)
+	}
+}
+
+// TestStreamableHTTPServerBidirectionalRequestHandlerError proves that a
+// client-side handler error surfaces back through SendRequestToClient rather
+// than being dropped.
+func TestStreamableHTTPServerBidirectionalRequestHandlerError(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.0.0")
+	done := make(chan error, 1)
+	srv.RegisterHandler("tools/call", func(ctx context.Context, params json.RawMessage) (any, error) {
+		_, err := srv.SendRequestToClient(ctx, "sampling/createMessage", map[string]any{"prompt": "say hi"})
+		done <- err
+		return nil, err
+	})
+
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(srv))
+	defer httpServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "initialize",
+	}); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	trans.RegisterRequestHandler("sampling/createMessage", func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		return nil, fmt.Errorf("user declined the request")
+	})
+
+	if _, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(2)),
+		Method:  "tools/call",
+	}); err != nil {
+		t.Fatalf("tools/call: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected SendRequestToClient to surface the client handler's error")
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for handler to observe SendRequestToClient's result")
+	}
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+)
+
+const (
+	wsServerPingInterval = 30 * time.Second
+	wsServerPongWait     = 60 * time.Second
+)
+
+// WebSocketServer serves an MCPServer over a persistent WebSocket
+// connection per client, as an alternative to StreamableHTTP for
+// deployments where a single full-duplex socket is preferable to HTTP+SSE.
+type WebSocketServer struct {
+	server   *MCPServer
+	upgrader websocket.Upgrader
+}
+
+// NewWebSocketServer wraps srv to be served over WebSocket connections.
+func NewWebSocketServer(srv *MCPServer) *WebSocketServer {
+	return &WebSocketServer{
+		server: srv,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// Origin checking is left to the caller, e.g. by wrapping
+			// ServeHTTP with their own middleware.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// ServeHTTP upgrades the connection and serves it until the client
+// disconnects or the connection errors out.
+func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	session := newWebSocketSession(conn)
+	s.server.RegisterSession(session)
+	defer s.server.UnregisterSession(session.SessionID())
+	defer conn.Close()
+
+	ctx := WithContext(r.Context(), session)
+
+	go session.writeLoop()
+	go session.pingLoop()
+
+	session.readLoop(ctx, s.server)
+}
+
+// webSocketSession implements ClientSession and RequestCapableSession on
+// top of a single WebSocket connection.
+type webSocketSession struct {
+	id   string
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+	notifCh chan mcp.JSONRPCNotification
+	closeCh chan struct{}
+
+	pendingMu sync.Mutex
+	pending   map[string]chan wsClientResponse
+
+	nextID int64
+
+	initialized bool
+}
+
+// wsClientResponse is what a pending SendRequestToClient call is waiting
+// for: either a result or an error decoded from the client's response.
+type wsClientResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+func newWebSocketSession(conn *websocket.Conn) *webSocketSession {
+	return &webSocketSession{
+		id:      fmt.Sprintf("ws-session-%p", conn),
+		conn:    conn,
+		notifCh: make(chan mcp.JSONRPCNotification, 100),
+		closeCh: make(chan struct{}),
+		pending: make(map[string]chan wsClientResponse),
+	}
+}
+
+func (s *webSocketSession) SessionID() string { return s.id }
+
+func (s *webSocketSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifCh }
+
+func (s *webSocketSession) Initialized() bool { return s.initialized }
+
+// SendRequestToClient sends method/params to the client over the socket and
+// blocks for its response.
+func (s *webSocketSession) SendRequestToClient(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	s.pendingMu.Lock()
+	s.nextID++
+	id := s.nextID
+	key := mcp.NewRequestId(id).String()
+	ch := make(chan wsClientResponse, 1)
+	s.pending[key] = ch
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, key)
+		s.pendingMu.Unlock()
+	}()
+
+	request := map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	}
+	if err := s.write(request); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.closeCh:
+		return nil, fmt.Errorf("session closed")
+	case resp := <-ch:
+		return resp.result, resp.err
+	}
+}
+
+func (s *webSocketSession) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// writeLoop forwards notifications enqueued on notifCh to the client.
+func (s *webSocketSession) writeLoop() {
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case notification, ok := <-s.notifCh:
+			if !ok {
+				return
+			}
+			_ = s.write(notification)
+		}
+	}
+}
+
+func (s *webSocketSession) pingLoop() {
+	ticker := time.NewTicker(wsServerPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			_ = s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
+			s.writeMu.Unlock()
+		}
+	}
+}
+
+// readLoop reads frames from the client for the lifetime of the connection,
+// dispatching requests/notifications to srv and responses to pending
+// SendRequestToClient calls.
+func (s *webSocketSession) readLoop(ctx context.Context, srv *MCPServer) {
+	defer close(s.closeCh)
+
+	s.conn.SetReadDeadline(time.Now().Add(wsServerPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		s.conn.SetReadDeadline(time.Now().Add(wsServerPongWait))
+		return nil
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var peek struct {
+			Method string          `json:"method"`
+			ID     *mcp.RequestId  `json:"id"`
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(data, &peek); err != nil {
+			continue
+		}
+
+		if peek.Method == "" {
+			// No method: this is the client's response to a request we
+			// sent it via SendRequestToClient.
+			var response struct {
+				ID     mcp.RequestId     `json:"id"`
+				Result json.RawMessage   `json:"result"`
+				Error  *mcp.JSONRPCError `json:"error"`
+			}
+			if err := json.Unmarshal(data, &response); err != nil {
+				continue
+			}
+			s.pendingMu.Lock()
+			ch, ok := s.pending[response.ID.String()]
+			s.pendingMu.Unlock()
+			if ok {
+				if response.Error != nil {
+					ch <- wsClientResponse{err: fmt.Errorf("client returned error %d: %s", response.Error.Code, response.Error.Message)}
+				} else {
+					ch <- wsClientResponse{result: response.Result}
+				}
+			}
+			continue
+		}
+
+		if peek.Method == "initialize" {
+			s.initialized = true
+		}
+
+		// Dispatched in its own goroutine so a handler that itself calls
+		// SendRequestToClient doesn't block readLoop from reading the
+		// client's response to that very request back off the same
+		// connection.
+		go func(message []byte) {
+			if response := srv.HandleMessage(ctx, s, message); response != nil {
+				_ = s.write(response)
+			}
+		}(data)
+	}
+}
@@ -0,0 +1,129 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KunalSin9h/modern-mcp-go/client/transport"
+	"github.com/KunalSin9h/modern-mcp-go/mcp"
+	"github.com/KunalSin9h/modern-mcp-go/server"
+)
+
+// TestWebSocketServerInitialize drives a real client.transport.WebSocket
+// against server.NewWebSocketServer end to end, covering the handshake that
+// readLoop's "initialize" special-case depends on MCPServer actually
+// answering.
+func TestWebSocketServerInitialize(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.2.3")
+	httpServer := httptest.NewServer(server.NewWebSocketServer(srv))
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	trans, err := transport.NewWebSocket(wsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer trans.Close()
+
+	resp, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "initialize",
+		Params:  map[string]any{"protocolVersion": "2025-03-26"},
+	})
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("expected a successful initialize response, got %+v", resp)
+	}
+
+	var result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.ProtocolVersion != "2025-03-26" {
+		t.Errorf("expected protocolVersion echoed back, got %q", result.ProtocolVersion)
+	}
+	if result.ServerInfo.Name != "test-server" || result.ServerInfo.Version != "1.2.3" {
+		t.Errorf("expected serverInfo {test-server 1.2.3}, got %+v", result.ServerInfo)
+	}
+}
+
+// TestWebSocketServerBidirectionalRequestHandlerError proves that a
+// client-side handler error surfaces back through SendRequestToClient as an
+// error, rather than being dropped and read back as a false (nil, nil)
+// success.
+func TestWebSocketServerBidirectionalRequestHandlerError(t *testing.T) {
+	srv := server.NewMCPServer("test-server", "1.0.0")
+	done := make(chan error, 1)
+	srv.RegisterHandler("tools/call", func(ctx context.Context, params json.RawMessage) (any, error) {
+		_, err := srv.SendRequestToClient(ctx, "sampling/createMessage", map[string]any{"prompt": "say hi"})
+		done <- err
+		return nil, err
+	})
+
+	httpServer := httptest.NewServer(server.NewWebSocketServer(srv))
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	trans, err := transport.NewWebSocket(wsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer trans.Close()
+
+	if _, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "initialize",
+	}); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	trans.RegisterRequestHandler("sampling/createMessage", func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		return nil, fmt.Errorf("user declined the request")
+	})
+
+	if _, err := trans.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(2)),
+		Method:  "tools/call",
+	}); err != nil {
+		t.Fatalf("tools/call: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected SendRequestToClient to surface the client handler's error")
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for handler to observe SendRequestToClient's result")
+	}
+}